@@ -0,0 +1,242 @@
+// Command proxy-server runs the multicluster proxy: it serves the hub-facing API, authorizes
+// callers, resolves each PipelineRun to the worker cluster Kueue admitted it to, and proxies the
+// request there.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	kueueclient "sigs.k8s.io/kueue/client-go/clientset/versioned"
+
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+
+	"github.com/khrm/proxy-aae/internal/authz"
+	"github.com/khrm/proxy-aae/internal/config"
+	"github.com/khrm/proxy-aae/internal/handlers"
+	"github.com/khrm/proxy-aae/internal/observability"
+	"github.com/khrm/proxy-aae/internal/registry"
+	"github.com/khrm/proxy-aae/internal/resolver"
+)
+
+func main() {
+	// Parse command line flags
+	var (
+		port                = flag.String("port", "8080", "Port to listen on")
+		workersSecretNS     = flag.String("workers-secret-namespace", "kueue-system", "Namespace for worker kubeconfig secrets")
+		requestTimeout      = flag.Duration("request-timeout", 30*time.Second, "Timeout for worker cluster requests")
+		defaultLogTailLines = flag.Int("default-log-tail-lines", 100, "Default number of log lines to tail")
+		kubeconfig          = flag.String("kubeconfig", "", "Path to kubeconfig file")
+
+		credentialSource          = flag.String("credential-source", "secret", "How to discover worker cluster credentials: secret, crd, or endpoint")
+		workerClusterCRDNamespace = flag.String("worker-cluster-crd-namespace", "kueue-system", "Namespace searched for WorkerCluster resources when --credential-source=crd")
+		credentialEndpointURL     = flag.String("credential-endpoint-url", "", "HTTP endpoint issuing worker kubeconfigs when --credential-source=endpoint")
+		credentialEndpointRefresh = flag.Duration("credential-endpoint-refresh-buffer", 0, "How long before expiry to refresh kubeconfigs fetched from --credential-endpoint-url (0 uses the source's default)")
+
+		authzBackend           = flag.String("authz-backend", "local", "Authorization strategy: local (SelfSubjectAccessReview with the caller's token), delegating (cached TokenReview + SubjectAccessReview), or oidc (OIDC ID token verification + claim-to-group mapping)")
+		authzIdentityCacheSize = flag.Int("authz-identity-cache-size", 1000, "Max resolved identities cached by the delegating authz backend (0 disables the cache)")
+		authzIdentityCacheTTL  = flag.Duration("authz-identity-cache-ttl", time.Minute, "How long a resolved identity stays cached by the delegating authz backend")
+		authzDecisionCacheSize = flag.Int("authz-decision-cache-size", 1000, "Max authorization decisions cached by the delegating or oidc authz backend (0 disables the cache)")
+		authzDecisionCacheTTL  = flag.Duration("authz-decision-cache-ttl", 10*time.Second, "How long an authorization decision stays cached by the delegating or oidc authz backend")
+
+		oidcIssuerURL     = flag.String("oidc-issuer-url", "", "OIDC issuer URL used to verify ID tokens when --authz-backend=oidc")
+		oidcClientID      = flag.String("oidc-client-id", "", "Expected audience (client ID) of ID tokens when --authz-backend=oidc")
+		oidcUsernameClaim = flag.String("oidc-username-claim", "sub", "ID token claim mapped to the Kubernetes username when --authz-backend=oidc")
+		oidcGroupsClaim   = flag.String("oidc-groups-claim", "groups", "ID token claim mapped to Kubernetes groups when --authz-backend=oidc")
+
+		tracingOTLPEndpoint = flag.String("tracing-otlp-endpoint", "", "OTLP/gRPC collector endpoint to export spans to (empty disables tracing)")
+		metricsAddr         = flag.String("metrics-addr", ":9090", "Address the Prometheus /metrics endpoint is served on")
+		auditLogPath        = flag.String("audit-log-path", "", "File path structured JSON audit events are appended to (empty logs to stdout)")
+	)
+	flag.Parse()
+
+	// Initialize klog
+	klog.InitFlags(nil)
+	flag.Set("logtostderr", "true")
+	flag.Set("v", "2")
+
+	// Load Kubernetes configuration
+	restConfig, err := loadKubeConfig(*kubeconfig)
+	if err != nil {
+		log.Fatalf("Failed to load kubeconfig: %v", err)
+	}
+
+	// Create Kubernetes clients
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		log.Fatalf("Failed to create kubernetes client: %v", err)
+	}
+
+	kueueClient, err := kueueclient.NewForConfig(restConfig)
+	if err != nil {
+		log.Fatalf("Failed to create kueue client: %v", err)
+	}
+
+	tektonClient, err := tektonclient.NewForConfig(restConfig)
+	if err != nil {
+		log.Fatalf("Failed to create tekton client: %v", err)
+	}
+
+	// Create configuration
+	appConfig := &config.Config{
+		WorkersSecretNamespace:          *workersSecretNS,
+		RequestTimeout:                  *requestTimeout,
+		DefaultLogTailLines:             *defaultLogTailLines,
+		AuthzIdentityCacheSize:          *authzIdentityCacheSize,
+		AuthzIdentityCacheTTL:           *authzIdentityCacheTTL,
+		AuthzDecisionCacheSize:          *authzDecisionCacheSize,
+		AuthzDecisionCacheTTL:           *authzDecisionCacheTTL,
+		CredentialSource:                *credentialSource,
+		WorkerClusterCRDNamespace:       *workerClusterCRDNamespace,
+		CredentialEndpointURL:           *credentialEndpointURL,
+		CredentialEndpointRefreshBuffer: *credentialEndpointRefresh,
+		TracingOTLPEndpoint:             *tracingOTLPEndpoint,
+		MetricsAddr:                     *metricsAddr,
+		AuditLogPath:                    *auditLogPath,
+		OIDCIssuerURL:                   *oidcIssuerURL,
+		OIDCClientID:                    *oidcClientID,
+		OIDCUsernameClaim:               *oidcUsernameClaim,
+		OIDCGroupsClaim:                 *oidcGroupsClaim,
+	}
+
+	ctx := context.Background()
+
+	shutdownTracing, err := observability.InitTracerProvider(ctx, appConfig.TracingOTLPEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(ctx)
+
+	metricsServer := observability.NewMetricsServer(appConfig.MetricsAddr)
+	go func() {
+		klog.Infof("Starting metrics server on %s", appConfig.MetricsAddr)
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			klog.Errorf("Metrics server failed: %v", err)
+		}
+	}()
+
+	auditLogger, err := newAuditLogger(appConfig.AuditLogPath)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+
+	credentialSrc, err := newCredentialSource(appConfig, restConfig, kubeClient, kueueClient)
+	if err != nil {
+		log.Fatalf("Failed to create credential source: %v", err)
+	}
+
+	// Initialize components
+	workloadResolver := resolver.NewWorkloadResolver(kubeClient, kueueClient, tektonClient, appConfig)
+	workerRegistry := registry.NewWorkerConfigRegistry(credentialSrc)
+	authzHandler, err := newAuthzHandler(ctx, *authzBackend, kubeClient, appConfig)
+	if err != nil {
+		log.Fatalf("Failed to create authz handler: %v", err)
+	}
+
+	// Create proxy server
+	proxyServer := handlers.NewProxyServerWithAudit(workloadResolver, workerRegistry, authzHandler, appConfig, auditLogger)
+
+	// Start HTTP server
+	server := &http.Server{
+		Addr:         ":" + *port,
+		Handler:      proxyServer.Handler(),
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	klog.Infof("Starting proxy server on port %s", *port)
+	klog.Infof("Credential source: %s", *credentialSource)
+	klog.Infof("Authz backend: %s", *authzBackend)
+	klog.Infof("Request timeout: %v", *requestTimeout)
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Server failed to start: %v", err)
+	}
+}
+
+// newAuditLogger opens path for appending and wraps it in an observability.AuditLogger, or logs to
+// stdout when path is empty.
+func newAuditLogger(path string) (*observability.AuditLogger, error) {
+	if path == "" {
+		return observability.NewAuditLogger(os.Stdout), nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return observability.NewAuditLogger(f), nil
+}
+
+// newCredentialSource builds the registry.CredentialSource named by cfg.CredentialSource.
+func newCredentialSource(cfg *config.Config, restConfig *rest.Config, kubeClient kubernetes.Interface, kueueClient kueueclient.Interface) (registry.CredentialSource, error) {
+	switch cfg.CredentialSource {
+	case "", "secret":
+		return registry.NewMultiKueueSecretSource(kubeClient, kueueClient, cfg), nil
+
+	case "crd":
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		return registry.NewWorkerClusterCRDSource(dynamicClient, kubeClient, cfg.WorkerClusterCRDNamespace), nil
+
+	case "endpoint":
+		return registry.NewEndpointCredentialSource(cfg.CredentialEndpointURL, cfg.CredentialEndpointRefreshBuffer), nil
+
+	default:
+		log.Fatalf("Unknown --credential-source %q: must be secret, crd, or endpoint", cfg.CredentialSource)
+		return nil, nil
+	}
+}
+
+// newAuthzHandler builds the AuthzHandler backed by the strategy named by backendName.
+func newAuthzHandler(ctx context.Context, backendName string, kubeClient kubernetes.Interface, cfg *config.Config) (*authz.AuthzHandler, error) {
+	switch backendName {
+	case "", "local":
+		return authz.NewAuthzHandler(kubeClient), nil
+
+	case "delegating":
+		backend := authz.NewDelegatingBackendWithCache(kubeClient, cfg.AuthzIdentityCacheSize, cfg.AuthzIdentityCacheTTL, cfg.AuthzDecisionCacheSize, cfg.AuthzDecisionCacheTTL)
+		return authz.NewAuthzHandlerWithBackend(backend, 0, 0), nil
+
+	case "oidc":
+		if cfg.OIDCIssuerURL == "" {
+			return nil, fmt.Errorf("--oidc-issuer-url is required when --authz-backend=oidc")
+		}
+		verifier, err := authz.NewJWTVerifier(ctx, cfg.OIDCIssuerURL, cfg.OIDCClientID, cfg.OIDCUsernameClaim, cfg.OIDCGroupsClaim)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OIDC verifier: %v", err)
+		}
+		backend := authz.NewOIDCBackend(verifier, kubeClient)
+		return authz.NewAuthzHandlerWithBackend(backend, cfg.AuthzDecisionCacheSize, cfg.AuthzDecisionCacheTTL), nil
+
+	default:
+		log.Fatalf("Unknown --authz-backend %q: must be local, delegating, or oidc", backendName)
+		return nil, nil
+	}
+}
+
+func loadKubeConfig(kubeconfigPath string) (*rest.Config, error) {
+	if kubeconfigPath != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	}
+
+	// Try in-cluster config first
+	if config, err := rest.InClusterConfig(); err == nil {
+		return config, nil
+	}
+
+	// Fall back to default kubeconfig
+	return clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+}
@@ -0,0 +1,127 @@
+package authz
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// decisionCacheEntry is the value stored behind a *list.Element in decisionCache.
+type decisionCacheEntry struct {
+	key       string
+	err       error
+	expiresAt time.Time
+}
+
+// decisionCache is a bounded, TTL-expiring LRU cache of authorization decisions, keyed by a
+// subject (a bearer token for AuthzHandler.cache, a resolved username for
+// DelegatingBackend.sarCache) and the requested access. The subject is salted and hashed before
+// use as a map key - see identityCache's doc comment - so that a heap dump doesn't leak bearer
+// tokens for callers that key this cache by token. A zero-value size or ttl disables caching: get
+// always misses and put is a no-op.
+type decisionCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	salt  []byte
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// newDecisionCache creates a decisionCache holding at most size entries, each valid for ttl.
+func newDecisionCache(size int, ttl time.Duration) *decisionCache {
+	if size <= 0 || ttl <= 0 {
+		return &decisionCache{}
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		klog.Errorf("Failed to generate decision cache salt, disabling decision cache: %v", err)
+		return &decisionCache{}
+	}
+
+	return &decisionCache{
+		size:  size,
+		ttl:   ttl,
+		salt:  salt,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached decision for subject/req, if one exists and has not expired.
+func (c *decisionCache) get(subject string, req AccessRequest) (error, bool) {
+	if c.items == nil {
+		return nil, false
+	}
+
+	key := c.cacheKey(subject, req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*decisionCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(elem)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return entry.err, true
+}
+
+// put records the outcome of authorizing subject/req, evicting the least recently used entry if
+// the cache is already at capacity.
+func (c *decisionCache) put(subject string, req AccessRequest, err error) {
+	if c.items == nil {
+		return
+	}
+
+	key := c.cacheKey(subject, req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*decisionCacheEntry)
+		entry.err = err
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	entry := &decisionCacheEntry{key: key, err: err, expiresAt: time.Now().Add(c.ttl)}
+	elem := c.ll.PushFront(entry)
+	c.items[key] = elem
+
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*decisionCacheEntry).key)
+		}
+	}
+}
+
+// cacheKey joins a hex-encoded HMAC-SHA256 of subject, salted with c.salt, and the access request
+// fields into a single lookup key. Hashing subject keeps raw bearer tokens out of the map even
+// though not every caller of decisionCache keys by token.
+func (c *decisionCache) cacheKey(subject string, req AccessRequest) string {
+	mac := hmac.New(sha256.New, c.salt)
+	mac.Write([]byte(subject))
+	hashedSubject := hex.EncodeToString(mac.Sum(nil))
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", hashedSubject, req.Namespace, req.Verb, req.Group, req.Version, req.Resource, req.Name)
+}
@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecisionCache_GetPut(t *testing.T) {
+	c := newDecisionCache(10, time.Minute)
+	req := AccessRequest{Namespace: "ns", Verb: "get", Resource: "pods", Name: "p1"}
+
+	if _, ok := c.get("token-a", req); ok {
+		t.Fatal("expected miss before put")
+	}
+
+	denied := fmt.Errorf("access denied")
+	c.put("token-a", req, denied)
+
+	got, ok := c.get("token-a", req)
+	if !ok {
+		t.Fatal("expected hit after put")
+	}
+	if got != denied {
+		t.Fatalf("got decision %v, want %v", got, denied)
+	}
+
+	if _, ok := c.get("token-b", req); ok {
+		t.Fatal("expected different subject to miss")
+	}
+}
+
+func TestDecisionCache_Expiry(t *testing.T) {
+	c := newDecisionCache(10, time.Millisecond)
+	req := AccessRequest{Namespace: "ns", Verb: "get", Resource: "pods", Name: "p1"}
+
+	c.put("token-a", req, nil)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.get("token-a", req); ok {
+		t.Fatal("expected entry to have expired")
+	}
+}
+
+func TestDecisionCache_DisabledWhenSizeOrTTLNonPositive(t *testing.T) {
+	c := newDecisionCache(0, time.Minute)
+	req := AccessRequest{Namespace: "ns", Verb: "get", Resource: "pods", Name: "p1"}
+
+	c.put("token-a", req, nil)
+	if _, ok := c.get("token-a", req); ok {
+		t.Fatal("expected caching to be disabled for a non-positive size")
+	}
+
+	c = newDecisionCache(10, 0)
+	c.put("token-a", req, nil)
+	if _, ok := c.get("token-a", req); ok {
+		t.Fatal("expected caching to be disabled for a non-positive TTL")
+	}
+}
+
+func TestDecisionCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newDecisionCache(1, time.Minute)
+	req := AccessRequest{Namespace: "ns", Verb: "get", Resource: "pods", Name: "p1"}
+
+	c.put("token-a", req, nil)
+	c.put("token-b", req, nil)
+
+	if _, ok := c.get("token-a", req); ok {
+		t.Fatal("expected least recently used entry to have been evicted")
+	}
+	if _, ok := c.get("token-b", req); !ok {
+		t.Fatal("expected most recently put entry to still be cached")
+	}
+}
+
+func TestDecisionCache_CacheKeyDoesNotContainRawSubject(t *testing.T) {
+	c := newDecisionCache(10, time.Minute)
+	req := AccessRequest{Namespace: "ns", Verb: "get", Resource: "pods", Name: "p1"}
+
+	token := "super-secret-bearer-token"
+	key := c.cacheKey(token, req)
+
+	if strings.Contains(key, token) {
+		t.Fatalf("cache key %q leaks the raw subject %q", key, token)
+	}
+}
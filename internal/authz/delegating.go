@@ -0,0 +1,157 @@
+package authz
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/khrm/proxy-aae/internal/metrics"
+)
+
+// DelegatingBackend authorizes by first resolving the caller's identity with a TokenReview, then
+// submitting a SubjectAccessReview on the caller's behalf using the proxy's own credentials. This
+// avoids handing the caller's bearer token to a fresh client per request (LocalBackend's
+// approach) at the cost of requiring the proxy's service account to hold "create" on
+// tokenreviews.authentication.k8s.io and subjectaccessreviews.authorization.k8s.io.
+//
+// Resolved identities and SubjectAccessReview decisions are each cached separately: the identity
+// cache saves a TokenReview round-trip for repeat callers, and the SAR cache is keyed by the
+// resolved username rather than the raw token so that two tokens belonging to the same user share
+// one cached decision.
+type DelegatingBackend struct {
+	kubeClient    kubernetes.Interface
+	identityCache *identityCache
+	sarCache      *decisionCache
+}
+
+// NewDelegatingBackend creates a DelegatingBackend that authenticates and authorizes callers
+// against the hub cluster using kubeClient's credentials, with caching disabled.
+func NewDelegatingBackend(kubeClient kubernetes.Interface) *DelegatingBackend {
+	return NewDelegatingBackendWithCache(kubeClient, 0, 0, 0, 0)
+}
+
+// NewDelegatingBackendWithCache creates a DelegatingBackend whose resolved identities are cached
+// for identityTTL (bounded to identitySize entries) and whose SubjectAccessReview decisions are
+// cached for sarTTL (bounded to sarSize entries). A non-positive size or TTL disables the
+// corresponding cache.
+func NewDelegatingBackendWithCache(kubeClient kubernetes.Interface, identitySize int, identityTTL time.Duration, sarSize int, sarTTL time.Duration) *DelegatingBackend {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		klog.Errorf("Failed to generate identity cache salt, disabling identity cache: %v", err)
+		identitySize = 0
+	}
+
+	return &DelegatingBackend{
+		kubeClient:    kubeClient,
+		identityCache: newIdentityCache(identitySize, identityTTL, salt),
+		sarCache:      newDecisionCache(sarSize, sarTTL),
+	}
+}
+
+// Authorize resolves the caller's identity via resolveIdentity, then submits a
+// SubjectAccessReview with the resolved user, groups, and extra carried over from the review
+// result, serving a cached decision if one is available for that user and AccessRequest.
+func (b *DelegatingBackend) Authorize(ctx context.Context, r *http.Request, req AccessRequest) error {
+	callerToken := extractBearerToken(r)
+	if callerToken == "" {
+		return fmt.Errorf("no authorization token provided")
+	}
+
+	user, err := b.resolveIdentity(ctx, callerToken)
+	if err != nil {
+		return err
+	}
+
+	if cached, ok := b.sarCache.get(user.Username, req); ok {
+		metrics.AuthzCacheResults.WithLabelValues("sar", "hit").Inc()
+		return cached
+	}
+	metrics.AuthzCacheResults.WithLabelValues("sar", "miss").Inc()
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: req.Namespace,
+				Verb:      req.Verb,
+				Group:     req.Group,
+				Version:   req.Version,
+				Resource:  req.Resource,
+				Name:      req.Name,
+			},
+		},
+	}
+
+	sarResult, err := b.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create SubjectAccessReview: %v", err)
+	}
+
+	var authzErr error
+	if !sarResult.Status.Allowed {
+		authzErr = fmt.Errorf("access denied to %s %s/%s for user %s: %s", req.Resource, req.Namespace, req.Name, user.Username, sarResult.Status.Reason)
+	}
+
+	b.sarCache.put(user.Username, req, authzErr)
+	return authzErr
+}
+
+// ReportIdentity resolves the caller's identity via resolveIdentity - consulting the identity
+// cache exactly like Authorize does - without submitting a SubjectAccessReview, so it stays cheap
+// to call purely for audit logging even when a cached SAR decision short-circuits Authorize.
+func (b *DelegatingBackend) ReportIdentity(ctx context.Context, r *http.Request) (string, error) {
+	token := extractBearerToken(r)
+	if token == "" {
+		return "", fmt.Errorf("no authorization token provided")
+	}
+
+	user, err := b.resolveIdentity(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+// resolveIdentity returns the user.Info a TokenReview resolves token to, serving a cached result
+// when one is available.
+func (b *DelegatingBackend) resolveIdentity(ctx context.Context, token string) (authenticationv1.UserInfo, error) {
+	if info, ok := b.identityCache.get(token); ok {
+		metrics.AuthzCacheResults.WithLabelValues("identity", "hit").Inc()
+		return info, nil
+	}
+	metrics.AuthzCacheResults.WithLabelValues("identity", "miss").Inc()
+
+	tr := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+
+	trResult, err := b.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, tr, v1.CreateOptions{})
+	if err != nil {
+		return authenticationv1.UserInfo{}, fmt.Errorf("failed to create TokenReview: %v", err)
+	}
+
+	if !trResult.Status.Authenticated {
+		return authenticationv1.UserInfo{}, fmt.Errorf("token review: caller not authenticated")
+	}
+
+	b.identityCache.put(token, trResult.Status.User)
+	return trResult.Status.User, nil
+}
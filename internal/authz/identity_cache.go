@@ -0,0 +1,116 @@
+package authz
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+// identityCacheEntry is the value stored behind a *list.Element in identityCache.
+type identityCacheEntry struct {
+	key       string
+	info      authenticationv1.UserInfo
+	expiresAt time.Time
+}
+
+// identityCache is a bounded, TTL-expiring LRU cache mapping a bearer token to the user.Info a
+// TokenReview resolved it to, so a burst of requests from the same caller costs at most one
+// TokenReview per TTL window instead of one per request. Entries are keyed by an HMAC of the
+// token, salted with a random per-process key, rather than the token itself, so a heap dump
+// doesn't leak bearer tokens. A zero-value size or ttl disables caching: get always misses and
+// put is a no-op.
+type identityCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	salt  []byte
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// newIdentityCache creates an identityCache holding at most size entries, each valid for ttl, with
+// cache keys salted with salt.
+func newIdentityCache(size int, ttl time.Duration, salt []byte) *identityCache {
+	if size <= 0 || ttl <= 0 {
+		return &identityCache{}
+	}
+	return &identityCache{
+		size:  size,
+		ttl:   ttl,
+		salt:  salt,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// hashToken returns a hex-encoded HMAC-SHA256 of token, salted with c.salt.
+func (c *identityCache) hashToken(token string) string {
+	mac := hmac.New(sha256.New, c.salt)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// get returns the cached identity for token, if one exists and has not expired.
+func (c *identityCache) get(token string) (authenticationv1.UserInfo, bool) {
+	if c.items == nil {
+		return authenticationv1.UserInfo{}, false
+	}
+
+	key := c.hashToken(token)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return authenticationv1.UserInfo{}, false
+	}
+
+	entry := elem.Value.(*identityCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(elem)
+		delete(c.items, key)
+		return authenticationv1.UserInfo{}, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return entry.info, true
+}
+
+// put records the identity a TokenReview resolved token to, evicting the least recently used
+// entry if the cache is already at capacity.
+func (c *identityCache) put(token string, info authenticationv1.UserInfo) {
+	if c.items == nil {
+		return
+	}
+
+	key := c.hashToken(token)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*identityCacheEntry)
+		entry.info = info
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	entry := &identityCacheEntry{key: key, info: info, expiresAt: time.Now().Add(c.ttl)}
+	elem := c.ll.PushFront(entry)
+	c.items[key] = elem
+
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*identityCacheEntry).key)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+func testSalt(t *testing.T) []byte {
+	t.Helper()
+	salt := make([]byte, 32)
+	for i := range salt {
+		salt[i] = byte(i)
+	}
+	return salt
+}
+
+func TestIdentityCache_GetPut(t *testing.T) {
+	c := newIdentityCache(10, time.Minute, testSalt(t))
+	info := authenticationv1.UserInfo{Username: "alice"}
+
+	if _, ok := c.get("token-a"); ok {
+		t.Fatal("expected miss before put")
+	}
+
+	c.put("token-a", info)
+
+	got, ok := c.get("token-a")
+	if !ok {
+		t.Fatal("expected hit after put")
+	}
+	if got.Username != "alice" {
+		t.Fatalf("got username %q, want %q", got.Username, "alice")
+	}
+}
+
+func TestIdentityCache_Expiry(t *testing.T) {
+	c := newIdentityCache(10, time.Millisecond, testSalt(t))
+	c.put("token-a", authenticationv1.UserInfo{Username: "alice"})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.get("token-a"); ok {
+		t.Fatal("expected entry to have expired")
+	}
+}
+
+func TestIdentityCache_DisabledWhenSizeOrTTLNonPositive(t *testing.T) {
+	c := newIdentityCache(0, time.Minute, testSalt(t))
+	c.put("token-a", authenticationv1.UserInfo{Username: "alice"})
+
+	if _, ok := c.get("token-a"); ok {
+		t.Fatal("expected caching to be disabled for a non-positive size")
+	}
+
+	c = newIdentityCache(10, 0, testSalt(t))
+	c.put("token-a", authenticationv1.UserInfo{Username: "alice"})
+	if _, ok := c.get("token-a"); ok {
+		t.Fatal("expected caching to be disabled for a non-positive TTL")
+	}
+}
+
+func TestIdentityCache_HashTokenDoesNotLeakRawToken(t *testing.T) {
+	c := newIdentityCache(10, time.Minute, testSalt(t))
+	token := "super-secret-bearer-token"
+
+	hashed := c.hashToken(token)
+
+	if strings.Contains(hashed, token) {
+		t.Fatalf("hashed token %q leaks the raw token %q", hashed, token)
+	}
+}
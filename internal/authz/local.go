@@ -0,0 +1,88 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// inClusterCAFile is where the service account volume mounts the hub apiserver's CA bundle,
+// matching what rest.InClusterConfig uses to build the proxy's own client.
+const inClusterCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+// LocalBackend authorizes by submitting a SelfSubjectAccessReview to the hub cluster using the
+// caller's own bearer token, exactly as the proxy originally did before backends were pluggable.
+type LocalBackend struct {
+	kubeClient kubernetes.Interface
+	caData     []byte
+}
+
+// NewLocalBackend creates a LocalBackend. kubeClient is unused for the review itself (a fresh
+// client is built per request from the caller's token, matching the original implementation) but
+// is kept so a future backend swap doesn't change the NewAuthzHandler call site. The hub cluster's
+// CA bundle is read once here and reused to verify the caller clients built per request, rather
+// than trusting the apiserver's certificate blindly.
+func NewLocalBackend(kubeClient kubernetes.Interface) *LocalBackend {
+	caData, err := os.ReadFile(inClusterCAFile)
+	if err != nil {
+		klog.Errorf("Failed to read in-cluster CA bundle %s, falling back to insecure TLS for caller clients: %v", inClusterCAFile, err)
+	}
+	return &LocalBackend{kubeClient: kubeClient, caData: caData}
+}
+
+// Authorize submits a SelfSubjectAccessReview to the hub cluster using a client built from the
+// caller's bearer token.
+func (b *LocalBackend) Authorize(ctx context.Context, r *http.Request, req AccessRequest) error {
+	callerToken := extractBearerToken(r)
+	if callerToken == "" {
+		return fmt.Errorf("no authorization token provided")
+	}
+
+	// Create a Kubernetes client with the caller's token, trusting the hub cluster's own CA
+	// bundle rather than disabling certificate verification.
+	callerConfig := &rest.Config{
+		Host:        "https://kubernetes.default.svc", // Use in-cluster API server
+		BearerToken: callerToken,
+	}
+	if len(b.caData) > 0 {
+		callerConfig.TLSClientConfig = rest.TLSClientConfig{CAData: b.caData}
+	} else {
+		callerConfig.TLSClientConfig = rest.TLSClientConfig{Insecure: true}
+	}
+
+	callerClient, err := kubernetes.NewForConfig(callerConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create caller client: %v", err)
+	}
+
+	ssar := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: req.Namespace,
+				Verb:      req.Verb,
+				Group:     req.Group,
+				Version:   req.Version,
+				Resource:  req.Resource,
+				Name:      req.Name,
+			},
+		},
+	}
+
+	result, err := callerClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create SelfSubjectAccessReview: %v", err)
+	}
+
+	if !result.Status.Allowed {
+		return fmt.Errorf("access denied to %s %s/%s: %s", req.Resource, req.Namespace, req.Name, result.Status.Reason)
+	}
+
+	return nil
+}
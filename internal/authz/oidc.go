@@ -0,0 +1,151 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// Claims holds the subset of an OIDC ID token's claims needed for authorization: the subject and
+// the groups it maps to.
+type Claims struct {
+	Subject string
+	Groups  []string
+}
+
+// TokenVerifier verifies a bearer token and returns the claims it carries. It is pluggable so
+// OIDCBackend does not depend on a specific JWT/JWKS library.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+// OIDCBackend authorizes callers by verifying their bearer token as an OIDC ID token, mapping its
+// claims to a Kubernetes user and groups, and submitting a SubjectAccessReview for that identity
+// against the hub cluster using kubeClient's credentials.
+type OIDCBackend struct {
+	verifier   TokenVerifier
+	kubeClient kubernetes.Interface
+}
+
+// NewOIDCBackend creates an OIDCBackend that verifies tokens with verifier and submits
+// SubjectAccessReviews through kubeClient.
+func NewOIDCBackend(verifier TokenVerifier, kubeClient kubernetes.Interface) *OIDCBackend {
+	return &OIDCBackend{verifier: verifier, kubeClient: kubeClient}
+}
+
+// Authorize verifies the caller's bearer token via the configured TokenVerifier and submits a
+// SubjectAccessReview for the resulting subject and groups.
+func (b *OIDCBackend) Authorize(ctx context.Context, r *http.Request, req AccessRequest) error {
+	token := extractBearerToken(r)
+	if token == "" {
+		return fmt.Errorf("no authorization token provided")
+	}
+
+	claims, err := b.verifier.Verify(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to verify OIDC token: %v", err)
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   claims.Subject,
+			Groups: claims.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: req.Namespace,
+				Verb:      req.Verb,
+				Group:     req.Group,
+				Version:   req.Version,
+				Resource:  req.Resource,
+				Name:      req.Name,
+			},
+		},
+	}
+
+	result, err := b.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create SubjectAccessReview: %v", err)
+	}
+
+	if !result.Status.Allowed {
+		return fmt.Errorf("access denied to %s %s/%s for subject %s: %s", req.Resource, req.Namespace, req.Name, claims.Subject, result.Status.Reason)
+	}
+
+	return nil
+}
+
+// JWTVerifier is a TokenVerifier that verifies OIDC ID tokens against an issuer's published JWKS
+// and maps configurable claims to a Kubernetes username and groups, the way kube-apiserver's own
+// --oidc-username-claim/--oidc-groups-claim flags do.
+type JWTVerifier struct {
+	verifier      *oidc.IDTokenVerifier
+	usernameClaim string
+	groupsClaim   string
+}
+
+// NewJWTVerifier discovers issuerURL's OIDC configuration and creates a JWTVerifier that checks
+// tokens are issued by it for clientID, mapping usernameClaim and groupsClaim from the token's
+// claims into Claims.Subject and Claims.Groups. A blank usernameClaim or groupsClaim falls back to
+// "sub" and "groups" respectively.
+func NewJWTVerifier(ctx context.Context, issuerURL, clientID, usernameClaim, groupsClaim string) (*JWTVerifier, error) {
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider %s: %v", issuerURL, err)
+	}
+
+	return &JWTVerifier{
+		verifier:      provider.Verifier(&oidc.Config{ClientID: clientID}),
+		usernameClaim: usernameClaim,
+		groupsClaim:   groupsClaim,
+	}, nil
+}
+
+// Verify checks token's signature and issuer/audience against the discovered provider, then maps
+// its claims to a Claims using the configured usernameClaim and groupsClaim.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	idToken, err := v.verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify ID token: %v", err)
+	}
+
+	var rawClaims map[string]interface{}
+	if err := idToken.Claims(&rawClaims); err != nil {
+		return nil, fmt.Errorf("failed to decode ID token claims: %v", err)
+	}
+
+	subject, _ := rawClaims[v.usernameClaim].(string)
+	if subject == "" {
+		subject = idToken.Subject
+	}
+
+	return &Claims{Subject: subject, Groups: stringSlice(rawClaims[v.groupsClaim])}, nil
+}
+
+// stringSlice converts a claim value decoded from JSON (normally a []interface{} of strings) into
+// a []string, skipping any element that isn't a string rather than failing the whole claim.
+func stringSlice(value interface{}) []string {
+	raw, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	groups := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			groups = append(groups, s)
+		}
+	}
+	return groups
+}
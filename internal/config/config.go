@@ -7,4 +7,57 @@ type Config struct {
 	WorkersSecretNamespace string
 	RequestTimeout         time.Duration
 	DefaultLogTailLines    int
+
+	// AuthzIdentityCacheSize and AuthzIdentityCacheTTL bound the DelegatingBackend's cache of
+	// TokenReview results. A non-positive size or TTL disables identity caching.
+	AuthzIdentityCacheSize int
+	AuthzIdentityCacheTTL  time.Duration
+
+	// AuthzDecisionCacheSize and AuthzDecisionCacheTTL bound the cache of authorization decisions:
+	// the DelegatingBackend's SubjectAccessReview cache, or the AuthzHandler's decision cache for
+	// the "oidc" backend. A non-positive size or TTL disables decision caching.
+	AuthzDecisionCacheSize int
+	AuthzDecisionCacheTTL  time.Duration
+
+	// CredentialSource selects how the WorkerConfigRegistry discovers worker cluster credentials:
+	// "secret" (MultiKueueCluster + kubeconfig Secret, the default), "crd" (WorkerCluster custom
+	// resources), or "endpoint" (a configured HTTP endpoint issuing short-lived kubeconfigs).
+	CredentialSource string
+
+	// WorkerClusterCRDNamespace is the namespace searched for WorkerCluster resources and their
+	// referenced auth secrets when CredentialSource is "crd".
+	WorkerClusterCRDNamespace string
+
+	// CredentialEndpointURL is the HTTP endpoint polled for worker kubeconfigs when
+	// CredentialSource is "endpoint".
+	CredentialEndpointURL string
+
+	// CredentialEndpointRefreshBuffer is how long before a kubeconfig's reported expiry the
+	// endpoint source refreshes it. A non-positive value falls back to a built-in default.
+	CredentialEndpointRefreshBuffer time.Duration
+
+	// TracingOTLPEndpoint is the OTLP/gRPC collector endpoint the proxy exports spans to. An
+	// empty value disables tracing.
+	TracingOTLPEndpoint string
+
+	// MetricsAddr is the address (host:port) the Prometheus /metrics endpoint is served on,
+	// separate from the proxy's main listener.
+	MetricsAddr string
+
+	// AuditLogPath is the file path structured JSON audit events are appended to. An empty value
+	// sends audit events to stdout.
+	AuditLogPath string
+
+	// OIDCIssuerURL is the OIDC issuer whose published JWKS verifies bearer tokens when
+	// --authz-backend is "oidc".
+	OIDCIssuerURL string
+
+	// OIDCClientID is the expected audience of ID tokens when --authz-backend is "oidc".
+	OIDCClientID string
+
+	// OIDCUsernameClaim and OIDCGroupsClaim are the ID token claims mapped to the Kubernetes
+	// username and groups submitted in the SubjectAccessReview when --authz-backend is "oidc". Empty
+	// values fall back to "sub" and "groups".
+	OIDCUsernameClaim string
+	OIDCGroupsClaim   string
 }
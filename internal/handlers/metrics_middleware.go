@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/khrm/proxy-aae/internal/metrics"
+	"github.com/khrm/proxy-aae/internal/observability"
+)
+
+// metricsResponseWriter wraps an http.ResponseWriter to capture the status code written, while
+// passing through the Flusher and Hijacker interfaces that the SSE and WebSocket handlers rely on.
+type metricsResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *metricsResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *metricsResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *metricsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// instrument wraps h so that, once it returns, the request's duration is recorded against
+// metrics.RequestDuration labeled by name, the worker cluster the handler resolved (read back from
+// the "X-Worker-Cluster" header on w; "unknown" when the handler did not set it there, as on error
+// paths and on WebSocket log streams, which send it only in the upgrade response), and the status
+// code written. When p.auditLogger is configured, it also attaches an observability audit recorder to
+// the request context; if the handler's authz check (internal/authz.AuthzHandler.authorize) fills
+// it in, the completed event - now carrying the resolved cluster and total latency too - is logged
+// through p.auditLogger. With no audit logger configured, the recorder is skipped entirely so
+// AuthzHandler.recordAudit's no-op guard trips immediately, instead of authorize() still paying for
+// a second TokenReview-based identity lookup whose result is then thrown away.
+func (p *ProxyServer) instrument(name string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &metricsResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		var auditEvent *observability.AuditEvent
+		if p.auditLogger != nil {
+			var ctx context.Context
+			ctx, auditEvent = observability.WithAuditRecorder(r.Context())
+			r = r.WithContext(ctx)
+		}
+		h(rw, r)
+
+		workerCluster := rw.Header().Get("X-Worker-Cluster")
+		if workerCluster == "" {
+			workerCluster = "unknown"
+		}
+		metrics.RequestDuration.WithLabelValues(name, workerCluster, strconv.Itoa(rw.statusCode)).Observe(time.Since(start).Seconds())
+
+		if auditEvent != nil && auditEvent.Verb != "" {
+			auditEvent.Cluster = workerCluster
+			auditEvent.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+			p.auditLogger.Log(*auditEvent)
+		}
+	}
+}
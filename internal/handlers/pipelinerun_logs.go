@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/gorilla/websocket"
+	"github.com/khrm/proxy-aae/internal/metrics"
+	"github.com/khrm/proxy-aae/internal/tracing"
+)
+
+// aggregatedLogChannelBuffer bounds how far the fan-in aggregator can get ahead of a slow
+// client before per-container readers start blocking, preserving back-pressure.
+const aggregatedLogChannelBuffer = 256
+
+// aggregatedLogLine is a single line read from one container's log stream, tagged with its
+// origin so it can be interleaved with lines from every other pod/container in the PipelineRun.
+type aggregatedLogLine struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message"`
+}
+
+// handlePipelineRunLogs handles /pipelineruns/{name}/logs, resolving the worker cluster once and
+// concurrently streaming logs from every container of every Pod labeled for the PipelineRun,
+// interleaved into a single ordered stream with "[pod/container]" prefixes (or, with
+// ?format=json, one JSON object per line).
+func (p *ProxyServer) handlePipelineRunLogs(w http.ResponseWriter, r *http.Request, namespace, pipelineRunName string) {
+	follow := r.URL.Query().Get("follow") == "true"
+	previous := r.URL.Query().Get("previous") == "true"
+	timestamps := r.URL.Query().Get("timestamps") == "true"
+	jsonFormat := r.URL.Query().Get("format") == "json"
+
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
+	if err != nil {
+		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
+		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if workerCluster.State != "Admitted" {
+		http.Error(w, "PipelineRun not admitted to worker cluster", http.StatusConflict)
+		return
+	}
+
+	_, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
+	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
+	if err != nil {
+		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
+		return
+	}
+
+	labelSelector := fmt.Sprintf("tekton.dev/pipelineRun=%s", pipelineRunName)
+	ctx, listSpan := tracing.Tracer().Start(ctx, "worker.listPods")
+	pods, err := p.listPods(ctx, workerCluster.Name, workerConfig, namespace, labelSelector)
+	listSpan.End()
+	if err != nil {
+		klog.Errorf("Failed to list Pods from worker cluster: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to list Pods: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if len(pods.Items) == 0 {
+		http.Error(w, "No Pods found for PipelineRun", http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
+
+	lines := make(chan aggregatedLogLine, aggregatedLogChannelBuffer)
+	var wg sync.WaitGroup
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			wg.Add(1)
+			go streamContainerLogLines(ctx, &wg, kubeClient, namespace, pod.Name, container.Name, follow, previous, timestamps, lines)
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	if wantsSSE(r) {
+		writeAggregatedLogsSSE(w, r, workerCluster.Name, lines, jsonFormat)
+	} else {
+		writeAggregatedLogsWS(w, r, workerCluster.Name, lines, jsonFormat)
+	}
+}
+
+// streamContainerLogLines reads a single container's log stream line by line and forwards each
+// line to out, tagged with its pod/container origin. It returns once the stream ends, the
+// context is cancelled, or the worker cluster cannot be reached.
+func streamContainerLogLines(ctx context.Context, wg *sync.WaitGroup, kubeClient kubernetes.Interface, namespace, podName, containerName string, follow, previous, timestamps bool, out chan<- aggregatedLogLine) {
+	defer wg.Done()
+
+	logOptions := &corev1.PodLogOptions{
+		Container:  containerName,
+		Follow:     follow,
+		Previous:   previous,
+		Timestamps: timestamps,
+	}
+
+	req := kubeClient.CoreV1().Pods(namespace).GetLogs(podName, logOptions)
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		klog.Errorf("Failed to stream logs for %s/%s: %v", podName, containerName, err)
+		return
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	for {
+		raw, err := reader.ReadString('\n')
+		if len(raw) > 0 {
+			message := strings.TrimRight(raw, "\n")
+			timestamp := ""
+			if timestamps {
+				if idx := strings.IndexByte(message, ' '); idx != -1 {
+					timestamp, message = message[:idx], message[idx+1:]
+				}
+			}
+
+			select {
+			case out <- aggregatedLogLine{Pod: podName, Container: containerName, Timestamp: timestamp, Message: message}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// formatAggregatedLogLine renders a line as "[pod/container] message" (prefixed with the
+// timestamp, if one was parsed out), or as a single JSON object when jsonFormat is set.
+func formatAggregatedLogLine(line aggregatedLogLine, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		payload, err := json.Marshal(line)
+		if err != nil {
+			return "", err
+		}
+		return string(payload), nil
+	}
+	if line.Timestamp != "" {
+		return fmt.Sprintf("%s [%s/%s] %s", line.Timestamp, line.Pod, line.Container, line.Message), nil
+	}
+	return fmt.Sprintf("[%s/%s] %s", line.Pod, line.Container, line.Message), nil
+}
+
+// writeAggregatedLogsWS streams the fan-in aggregated lines to the client over a WebSocket
+// connection, one text message per line.
+func writeAggregatedLogsWS(w http.ResponseWriter, r *http.Request, clusterName string, lines <-chan aggregatedLogLine, jsonFormat bool) {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return true // Allow all origins for now
+		},
+	}
+	conn, err := upgrader.Upgrade(w, r, http.Header{"X-Worker-Cluster": []string{clusterName}})
+	if err != nil {
+		klog.Errorf("Failed to upgrade to WebSocket for aggregated logs: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	metrics.ActiveLogStreams.Inc()
+	defer metrics.ActiveLogStreams.Dec()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			formatted, err := formatAggregatedLogLine(line, jsonFormat)
+			if err != nil {
+				klog.Errorf("Failed to format aggregated log line: %v", err)
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(formatted)); err != nil {
+				klog.Errorf("Error writing aggregated logs to WebSocket: %v", err)
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeAggregatedLogsSSE streams the fan-in aggregated lines to the client as Server-Sent Events,
+// with the same heartbeat and byte-offset "id:" framing as the single-container SSE log stream.
+func writeAggregatedLogsSSE(w http.ResponseWriter, r *http.Request, clusterName string, lines <-chan aggregatedLogLine, jsonFormat bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	metrics.ActiveLogStreams.Inc()
+	defer metrics.ActiveLogStreams.Dec()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Worker-Cluster", clusterName)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	var offset int64
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			formatted, err := formatAggregatedLogLine(line, jsonFormat)
+			if err != nil {
+				klog.Errorf("Failed to format aggregated log line: %v", err)
+				continue
+			}
+			offset += int64(len(formatted))
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", offset, formatted)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,15 +14,23 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"github.com/gorilla/websocket"
 	"github.com/khrm/proxy-aae/internal/authz"
 	"github.com/khrm/proxy-aae/internal/config"
+	"github.com/khrm/proxy-aae/internal/metrics"
+	"github.com/khrm/proxy-aae/internal/observability"
 	"github.com/khrm/proxy-aae/internal/registry"
 	"github.com/khrm/proxy-aae/internal/resolver"
+	"github.com/khrm/proxy-aae/internal/tracing"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ProxyServer handles HTTP requests and proxies them to worker clusters
@@ -28,33 +39,65 @@ type ProxyServer struct {
 	workerRegistry *registry.WorkerConfigRegistry
 	authzHandler   *authz.AuthzHandler
 	config         *config.Config
+	auditLogger    *observability.AuditLogger
 }
 
-// NewProxyServer creates a new ProxyServer
+// NewProxyServer creates a new ProxyServer with audit logging disabled.
 func NewProxyServer(
 	resolver *resolver.WorkloadResolver,
 	workerRegistry *registry.WorkerConfigRegistry,
 	authzHandler *authz.AuthzHandler,
 	config *config.Config,
+) *ProxyServer {
+	return NewProxyServerWithAudit(resolver, workerRegistry, authzHandler, config, nil)
+}
+
+// NewProxyServerWithAudit creates a new ProxyServer that logs a structured audit event for every
+// request whose authz check runs, through auditLogger. A nil auditLogger disables audit logging.
+func NewProxyServerWithAudit(
+	resolver *resolver.WorkloadResolver,
+	workerRegistry *registry.WorkerConfigRegistry,
+	authzHandler *authz.AuthzHandler,
+	config *config.Config,
+	auditLogger *observability.AuditLogger,
 ) *ProxyServer {
 	return &ProxyServer{
 		resolver:       resolver,
 		workerRegistry: workerRegistry,
 		authzHandler:   authzHandler,
 		config:         config,
+		auditLogger:    auditLogger,
 	}
 }
 
-// Handler returns the HTTP handler for the proxy server
+// Handler returns the HTTP handler for the proxy server, instrumented end-to-end with OTel spans.
+// Prometheus metrics are served separately by observability.NewMetricsServer, so scraping them
+// never competes with proxied traffic on this handler's listener.
 func (p *ProxyServer) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// API endpoints
-	mux.HandleFunc("/api/v1/namespaces/", p.handleNamespaceRequest)
-	mux.HandleFunc("/health", p.handleHealth)
-	mux.HandleFunc("/ready", p.handleReady)
+	mux.HandleFunc("/api/v1/namespaces/", p.instrument("namespaces", p.handleNamespaceRequest))
+	mux.HandleFunc("/health", p.instrument("health", p.handleHealth))
+	mux.HandleFunc("/ready", p.instrument("ready", p.handleReady))
+
+	return observability.WrapHandler(mux)
+}
 
-	return mux
+// annotateResolveSpan records the PipelineRun, Workload, and resolved cluster names on a
+// resolver.ResolveWorkerCluster span so traces show why a request landed on a particular worker.
+// cluster is nil when resolution failed, in which case only the PipelineRun is recorded.
+func annotateResolveSpan(span trace.Span, namespace, pipelineRunName string, cluster *resolver.WorkerCluster) {
+	span.SetAttributes(
+		attribute.String("pipelinerun.namespace", namespace),
+		attribute.String("pipelinerun.name", pipelineRunName),
+	)
+	if cluster != nil {
+		span.SetAttributes(
+			attribute.String("workload.name", cluster.WorkloadName),
+			attribute.String("cluster.name", cluster.Name),
+		)
+	}
 }
 
 // handleNamespaceRequest handles requests to /api/v1/namespaces/{namespace}/...
@@ -96,6 +139,7 @@ func (p *ProxyServer) handlePipelineRunRequest(w http.ResponseWriter, r *http.Re
 
 	// Check authorization
 	if err := p.authzHandler.CheckPipelineRunAccess(r.Context(), r, namespace, pipelineRunName); err != nil {
+		metrics.AuthzDenials.WithLabelValues("pipelineruns").Inc()
 		http.Error(w, fmt.Sprintf("Access denied: %v", err), http.StatusForbidden)
 		return
 	}
@@ -108,6 +152,8 @@ func (p *ProxyServer) handlePipelineRunRequest(w http.ResponseWriter, r *http.Re
 		p.handleTaskRuns(w, r, namespace, pipelineRunName)
 	case "pods":
 		p.handlePipelineRunPods(w, r, namespace, pipelineRunName)
+	case "logs":
+		p.handlePipelineRunLogs(w, r, namespace, pipelineRunName)
 	default:
 		http.Error(w, "Unknown PipelineRun sub-resource", http.StatusNotFound)
 	}
@@ -116,7 +162,10 @@ func (p *ProxyServer) handlePipelineRunRequest(w http.ResponseWriter, r *http.Re
 // handleResolve handles /resolve endpoint
 func (p *ProxyServer) handleResolve(w http.ResponseWriter, r *http.Request, namespace, pipelineRunName string) {
 	// Resolve worker cluster
-	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	ctx, span := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(span, namespace, pipelineRunName, workerCluster)
+	span.End()
 	if err != nil {
 		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s/%s: %v", namespace, pipelineRunName, err)
 		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusNotFound)
@@ -145,7 +194,10 @@ func (p *ProxyServer) handleResolve(w http.ResponseWriter, r *http.Request, name
 // handleTaskRuns handles /taskruns endpoint
 func (p *ProxyServer) handleTaskRuns(w http.ResponseWriter, r *http.Request, namespace, pipelineRunName string) {
 	// Resolve worker cluster
-	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s/%s: %v", namespace, pipelineRunName, err)
 		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusNotFound)
@@ -158,21 +210,27 @@ func (p *ProxyServer) handleTaskRuns(w http.ResponseWriter, r *http.Request, nam
 	}
 
 	// Get worker config
+	_, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
 	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
 		return
 	}
 
-	// Create Tekton client for worker cluster
-	tektonClient := tektonclient.NewForConfigOrDie(workerConfig)
-
-	// List TaskRuns with label selector
 	labelSelector := fmt.Sprintf("tekton.dev/pipelineRun=%s", pipelineRunName)
-	taskRuns, err := tektonClient.TektonV1().TaskRuns(namespace).List(r.Context(), v1.ListOptions{
-		LabelSelector: labelSelector,
-	})
+
+	if r.URL.Query().Get("watch") == "true" {
+		w.Header().Set("X-Worker-Cluster", workerCluster.Name)
+		p.watchTaskRuns(w, r, workerConfig, namespace, labelSelector)
+		return
+	}
+
+	// Serve from the informer-backed cache when it is warm, falling back to a live call otherwise
+	ctx, listSpan := tracing.Tracer().Start(ctx, "worker.listTaskRuns")
+	taskRuns, err := p.listTaskRuns(ctx, workerCluster.Name, workerConfig, namespace, labelSelector)
+	listSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to list TaskRuns from worker cluster: %v", err)
 		http.Error(w, fmt.Sprintf("Failed to list TaskRuns: %v", err), http.StatusInternalServerError)
@@ -192,7 +250,10 @@ func (p *ProxyServer) handleTaskRuns(w http.ResponseWriter, r *http.Request, nam
 // handlePipelineRunPods handles /pods endpoint for PipelineRun
 func (p *ProxyServer) handlePipelineRunPods(w http.ResponseWriter, r *http.Request, namespace, pipelineRunName string) {
 	// Resolve worker cluster
-	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s/%s: %v", namespace, pipelineRunName, err)
 		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusNotFound)
@@ -205,21 +266,27 @@ func (p *ProxyServer) handlePipelineRunPods(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Get worker config
+	_, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
 	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
 		return
 	}
 
-	// Create Kubernetes client for worker cluster
-	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
-
-	// List Pods with label selector
 	labelSelector := fmt.Sprintf("tekton.dev/pipelineRun=%s", pipelineRunName)
-	pods, err := kubeClient.CoreV1().Pods(namespace).List(r.Context(), v1.ListOptions{
-		LabelSelector: labelSelector,
-	})
+
+	if r.URL.Query().Get("watch") == "true" {
+		w.Header().Set("X-Worker-Cluster", workerCluster.Name)
+		p.watchPods(w, r, workerConfig, namespace, labelSelector)
+		return
+	}
+
+	// Serve from the informer-backed cache when it is warm, falling back to a live call otherwise
+	ctx, listSpan := tracing.Tracer().Start(ctx, "worker.listPods")
+	pods, err := p.listPods(ctx, workerCluster.Name, workerConfig, namespace, labelSelector)
+	listSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to list Pods from worker cluster: %v", err)
 		http.Error(w, fmt.Sprintf("Failed to list Pods: %v", err), http.StatusInternalServerError)
@@ -236,6 +303,70 @@ func (p *ProxyServer) handlePipelineRunPods(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// listPods lists Pods matching labelSelector from the worker's informer cache, falling back to a
+// live call when the cache for this worker is missing or has not finished its initial sync.
+func (p *ProxyServer) listPods(ctx context.Context, clusterName string, workerConfig *rest.Config, namespace, labelSelector string) (*corev1.PodList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", labelSelector, err)
+	}
+
+	if workerCache, ok := p.workerRegistry.GetCache(clusterName); ok && workerCache.Synced() {
+		pods, err := workerCache.PodLister.Pods(namespace).List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Pods from cache: %v", err)
+		}
+		list := &corev1.PodList{}
+		for _, pod := range pods {
+			list.Items = append(list.Items, *pod)
+		}
+		return list, nil
+	}
+
+	klog.V(4).Infof("Pod cache for cluster %s is cold, falling back to live call", clusterName)
+	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
+	return kubeClient.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{LabelSelector: labelSelector})
+}
+
+// getPod fetches a single Pod from the worker's informer cache, falling back to a live call when
+// the cache for this worker is missing, has not synced, or does not have the Pod.
+func (p *ProxyServer) getPod(ctx context.Context, clusterName string, workerConfig *rest.Config, namespace, podName string) (*corev1.Pod, error) {
+	if workerCache, ok := p.workerRegistry.GetCache(clusterName); ok && workerCache.Synced() {
+		if pod, err := workerCache.PodLister.Pods(namespace).Get(podName); err == nil {
+			return pod, nil
+		}
+	}
+
+	klog.V(4).Infof("Pod cache for cluster %s is cold or missing %s/%s, falling back to live call", clusterName, namespace, podName)
+	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
+	return kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, v1.GetOptions{})
+}
+
+// listTaskRuns lists TaskRuns matching labelSelector from the worker's informer cache, falling
+// back to a live call when the cache for this worker is missing or has not finished its initial sync.
+func (p *ProxyServer) listTaskRuns(ctx context.Context, clusterName string, workerConfig *rest.Config, namespace, labelSelector string) (*pipelinev1.TaskRunList, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %v", labelSelector, err)
+	}
+
+	if workerCache, ok := p.workerRegistry.GetCache(clusterName); ok && workerCache.Synced() {
+		taskRuns, err := workerCache.TaskRunLister.TaskRuns(namespace).List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list TaskRuns from cache: %v", err)
+		}
+		list := &pipelinev1.TaskRunList{}
+		for _, taskRun := range taskRuns {
+			list.Items = append(list.Items, *taskRun)
+		}
+		return list, nil
+	}
+
+	klog.V(4).Infof("TaskRun cache for cluster %s is cold, falling back to live call", clusterName)
+	tektonClient := tektonclient.NewForConfigOrDie(workerConfig)
+	return tektonClient.TektonV1().TaskRuns(namespace).List(ctx, v1.ListOptions{LabelSelector: labelSelector})
+}
+
 // handlePodRequest handles Pod-related requests
 func (p *ProxyServer) handlePodRequest(w http.ResponseWriter, r *http.Request, namespace, resourcePath string) {
 	// Parse Pod name from path
@@ -250,6 +381,7 @@ func (p *ProxyServer) handlePodRequest(w http.ResponseWriter, r *http.Request, n
 
 	// Check authorization
 	if err := p.authzHandler.CheckPodAccess(r.Context(), r, namespace, podName); err != nil {
+		metrics.AuthzDenials.WithLabelValues("pods").Inc()
 		http.Error(w, fmt.Sprintf("Access denied: %v", err), http.StatusForbidden)
 		return
 	}
@@ -258,6 +390,12 @@ func (p *ProxyServer) handlePodRequest(w http.ResponseWriter, r *http.Request, n
 	switch subPath {
 	case "status":
 		p.handlePodStatus(w, r, namespace, podName)
+	case "exec":
+		p.handlePodExec(w, r, namespace, podName)
+	case "attach":
+		p.handlePodAttach(w, r, namespace, podName)
+	case "portforward":
+		p.handlePodPortForward(w, r, namespace, podName)
 	default:
 		http.Error(w, "Unknown Pod sub-resource", http.StatusNotFound)
 	}
@@ -273,7 +411,10 @@ func (p *ProxyServer) handlePodStatus(w http.ResponseWriter, r *http.Request, na
 	}
 
 	// Resolve worker cluster using PipelineRun
-	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
 		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
@@ -286,18 +427,19 @@ func (p *ProxyServer) handlePodStatus(w http.ResponseWriter, r *http.Request, na
 	}
 
 	// Get worker config
+	_, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
 	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
 		return
 	}
 
-	// Create Kubernetes client for the worker cluster
-	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
-
-	// Get pod status from worker cluster
-	pod, err := kubeClient.CoreV1().Pods(namespace).Get(r.Context(), podName, v1.GetOptions{})
+	// Get pod status from worker cluster, preferring the informer-backed cache
+	ctx, getSpan := tracing.Tracer().Start(ctx, "worker.getPod")
+	pod, err := p.getPod(ctx, workerCluster.Name, workerConfig, namespace, podName)
+	getSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get pod %s from worker cluster %s: %v", podName, workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Failed to get pod: %v", err), http.StatusInternalServerError)
@@ -327,18 +469,31 @@ func (p *ProxyServer) handleLogsRequest(w http.ResponseWriter, r *http.Request,
 
 	// Check authorization
 	if err := p.authzHandler.CheckPodLogsAccess(r.Context(), r, namespace, podName); err != nil {
+		metrics.AuthzDenials.WithLabelValues("logs").Inc()
 		http.Error(w, fmt.Sprintf("Access denied: %v", err), http.StatusForbidden)
 		return
 	}
 
 	// Route to specific handler
-	if strings.HasSuffix(resourcePath, "/stream") {
+	switch {
+	case strings.HasSuffix(resourcePath, "/stream") && wantsSSE(r):
+		p.handleLogsStreamSSE(w, r, namespace, podName, containerName)
+	case strings.HasSuffix(resourcePath, "/stream"):
 		p.handleLogsStream(w, r, namespace, podName, containerName)
-	} else {
+	default:
 		p.handleLogsFetch(w, r, namespace, podName, containerName)
 	}
 }
 
+// wantsSSE reports whether the caller asked for the Server-Sent Events log transport instead of
+// the default WebSocket one, via the Accept header or the ?transport=sse query parameter.
+func wantsSSE(r *http.Request) bool {
+	if r.URL.Query().Get("transport") == "sse" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
 // handleLogsFetch handles HTTP logs fetching
 func (p *ProxyServer) handleLogsFetch(w http.ResponseWriter, r *http.Request, namespace, podName, containerName string) {
 	// Parse query parameters
@@ -367,7 +522,10 @@ func (p *ProxyServer) handleLogsFetch(w http.ResponseWriter, r *http.Request, na
 	}
 
 	// Resolve worker cluster using PipelineRun
-	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
 		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
@@ -380,7 +538,9 @@ func (p *ProxyServer) handleLogsFetch(w http.ResponseWriter, r *http.Request, na
 	}
 
 	// Get worker config
+	ctx, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
 	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
@@ -401,8 +561,10 @@ func (p *ProxyServer) handleLogsFetch(w http.ResponseWriter, r *http.Request, na
 	}
 
 	// Get logs from the worker cluster
+	_, streamSpan := tracing.Tracer().Start(ctx, "worker.GetLogs")
 	req := kubeClient.CoreV1().Pods(namespace).GetLogs(podName, logOptions)
-	logs, err := req.Stream(r.Context())
+	logs, err := req.Stream(ctx)
+	streamSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get logs from worker cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Failed to get logs: %v", err), http.StatusInternalServerError)
@@ -432,7 +594,10 @@ func (p *ProxyServer) handleLogsStream(w http.ResponseWriter, r *http.Request, n
 	}
 
 	// Resolve worker cluster using PipelineRun
-	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
 		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
@@ -445,7 +610,9 @@ func (p *ProxyServer) handleLogsStream(w http.ResponseWriter, r *http.Request, n
 	}
 
 	// Get worker config
+	ctx, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
 	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
@@ -462,8 +629,10 @@ func (p *ProxyServer) handleLogsStream(w http.ResponseWriter, r *http.Request, n
 	}
 
 	// Get logs stream from the worker cluster
+	_, streamSpan := tracing.Tracer().Start(ctx, "worker.GetLogs")
 	req := kubeClient.CoreV1().Pods(namespace).GetLogs(podName, logOptions)
-	logs, err := req.Stream(r.Context())
+	logs, err := req.Stream(ctx)
+	streamSpan.End()
 	if err != nil {
 		klog.Errorf("Failed to get logs stream from worker cluster %s: %v", workerCluster.Name, err)
 		http.Error(w, fmt.Sprintf("Failed to get logs stream: %v", err), http.StatusInternalServerError)
@@ -484,9 +653,25 @@ func (p *ProxyServer) handleLogsStream(w http.ResponseWriter, r *http.Request, n
 	}
 	defer conn.Close()
 
+	metrics.ActiveLogStreams.Inc()
+	defer metrics.ActiveLogStreams.Dec()
+
 	// Set WebSocket headers
 	conn.SetWriteDeadline(time.Now().Add(24 * time.Hour)) // 24 hour timeout
 
+	// Close the upstream log stream as soon as the client goes away, so the read loop below
+	// unblocks instead of leaking until the worker cluster ends the stream on its own. The
+	// connection is hijacked, so the request context is not cancelled when the client leaves; a
+	// failed read from the WebSocket is what reports the disconnect.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				logs.Close()
+				return
+			}
+		}
+	}()
+
 	// Stream logs to WebSocket client
 	buffer := make([]byte, 1024)
 	for {
@@ -506,6 +691,156 @@ func (p *ProxyServer) handleLogsStream(w http.ResponseWriter, r *http.Request, n
 	}
 }
 
+// sseHeartbeatInterval is how often a comment-only SSE frame is sent to keep the connection alive
+// through proxies that otherwise time out an idle response.
+const sseHeartbeatInterval = 15 * time.Second
+
+// handleLogsStreamSSE handles WebSocket logs streaming's Server-Sent Events counterpart, for
+// browsers behind proxies that strip Upgrade headers and for curl-based CLI clients. Each log
+// chunk is framed as a "data:" event with an "id:" set to the cumulative byte offset, so clients
+// can reconnect with a "Last-Event-ID" header to resume.
+func (p *ProxyServer) handleLogsStreamSSE(w http.ResponseWriter, r *http.Request, namespace, podName, containerName string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Extract PipelineRun name from query parameter
+	pipelineRunName := r.URL.Query().Get("pipelineRun")
+	if pipelineRunName == "" {
+		http.Error(w, "PipelineRun name must be provided as query parameter 'pipelineRun'", http.StatusBadRequest)
+		return
+	}
+
+	// Resolve worker cluster using PipelineRun
+	ctx, resolveSpan := tracing.Tracer().Start(r.Context(), "resolver.ResolveWorkerCluster")
+	workerCluster, err := p.resolver.ResolveWorkerCluster(ctx, namespace, pipelineRunName)
+	annotateResolveSpan(resolveSpan, namespace, pipelineRunName, workerCluster)
+	resolveSpan.End()
+	if err != nil {
+		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
+		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if workerCluster.State != "Admitted" {
+		http.Error(w, "PipelineRun not admitted to worker cluster", http.StatusConflict)
+		return
+	}
+
+	// Get worker config
+	ctx, configSpan := tracing.Tracer().Start(ctx, "registry.GetConfig")
+	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	configSpan.End()
+	if err != nil {
+		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
+		return
+	}
+
+	// Create Kubernetes client for the worker cluster
+	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
+
+	// Set up log options for streaming
+	logOptions := &corev1.PodLogOptions{
+		Container: containerName,
+		Follow:    true,
+	}
+
+	// Get logs stream from the worker cluster
+	_, streamSpan := tracing.Tracer().Start(ctx, "worker.GetLogs")
+	req := kubeClient.CoreV1().Pods(namespace).GetLogs(podName, logOptions)
+	logs, err := req.Stream(ctx)
+	streamSpan.End()
+	if err != nil {
+		klog.Errorf("Failed to get logs stream from worker cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Failed to get logs stream: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer logs.Close()
+
+	metrics.ActiveLogStreams.Inc()
+	defer metrics.ActiveLogStreams.Dec()
+
+	// Close the upstream log stream as soon as the client disconnects, so the read loop below
+	// unblocks instead of leaking until the worker cluster ends the stream on its own.
+	go func() {
+		<-r.Context().Done()
+		logs.Close()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Worker-Cluster", workerCluster.Name)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// The Kubernetes log API has no way to seek a live stream to a byte position, and the stream
+	// above starts from the beginning of the container's log, so a reconnect with Last-Event-ID
+	// reads and discards that many bytes before relaying anything.
+	var offset int64
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		if parsed, err := strconv.ParseInt(lastEventID, 10, 64); err == nil {
+			if _, err := io.CopyN(io.Discard, logs, parsed); err != nil {
+				klog.Errorf("Failed to skip to byte offset %d of SSE log stream for %s/%s: %v", parsed, podName, containerName, err)
+				return
+			}
+			offset = parsed
+			klog.V(4).Infof("Resuming SSE log stream for %s/%s from byte offset %d", podName, containerName, offset)
+		}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	lines := make(chan []byte)
+	readErrCh := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(logs)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				select {
+				case readErrCh <- err:
+				case <-done:
+				}
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case line := <-lines:
+			offset += int64(len(line))
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", offset, bytes.TrimRight(line, "\n"))
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case err := <-readErrCh:
+			if err != io.EOF {
+				klog.Errorf("Error reading SSE logs stream: %v", err)
+			}
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
 // handleHealth handles health check endpoint
 func (p *ProxyServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
@@ -521,6 +856,16 @@ func (p *ProxyServer) handleReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if unsynced := p.workerRegistry.UnsyncedClusters(); len(unsynced) > 0 {
+		http.Error(w, fmt.Sprintf("Worker cluster caches not yet synced: %s", strings.Join(unsynced, ", ")), http.StatusServiceUnavailable)
+		return
+	}
+
+	if !p.resolver.CachesSynced() {
+		http.Error(w, "Workload resolver caches not yet synced", http.StatusServiceUnavailable)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Ready"))
 }
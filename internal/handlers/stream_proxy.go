@@ -0,0 +1,785 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	spdystream "k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/klog/v2"
+
+	"github.com/gorilla/websocket"
+	"github.com/khrm/proxy-aae/internal/metrics"
+)
+
+// execWebsocketSubprotocol mirrors the Kubernetes API server's "v5.channel.k8s.io" exec
+// subprotocol, which multiplexes stdin/stdout/stderr/error/resize over a single websocket
+// connection with a one-byte channel prefix on every frame.
+const execWebsocketSubprotocol = "v5.channel.k8s.io"
+
+type remoteCommandChannel byte
+
+const (
+	channelStdin  remoteCommandChannel = 0
+	channelStdout remoteCommandChannel = 1
+	channelStderr remoteCommandChannel = 2
+	channelError  remoteCommandChannel = 3
+	channelResize remoteCommandChannel = 4
+)
+
+var execStreamUpgrader = websocket.Upgrader{
+	Subprotocols: []string{execWebsocketSubprotocol},
+	CheckOrigin: func(r *http.Request) bool {
+		return true // Worker routing is authorized upstream; origin is not a trust boundary here
+	},
+}
+
+// spdyRemoteCommandProtocols lists the raw-SPDY exec/attach subprotocols this proxy negotiates
+// with the client, newest first, mirroring the API server's own negotiation order. Older kubectl
+// and oc releases default to one of these instead of the "v5.channel.k8s.io" WebSocket protocol.
+var spdyRemoteCommandProtocols = []string{
+	"v4.channel.k8s.io",
+	"v3.channel.k8s.io",
+	"v2.channel.k8s.io",
+	"channel.k8s.io",
+}
+
+// isSPDYUpgrade reports whether the client requested a raw SPDY upgrade, the transport classic
+// kubectl/oc exec, attach, and port-forward implementations use, rather than a WebSocket upgrade.
+func isSPDYUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "SPDY/3.1")
+}
+
+// handlePodExec handles /pods/{pod}/exec, bridging an interactive "oc exec"/"kubectl exec"
+// session through to the pod's resolved worker cluster.
+func (p *ProxyServer) handlePodExec(w http.ResponseWriter, r *http.Request, namespace, podName string) {
+	p.proxyRemoteCommand(w, r, namespace, podName, "exec")
+}
+
+// handlePodAttach handles /pods/{pod}/attach, bridging an "oc attach"/"kubectl attach"
+// session through to the pod's resolved worker cluster.
+func (p *ProxyServer) handlePodAttach(w http.ResponseWriter, r *http.Request, namespace, podName string) {
+	p.proxyRemoteCommand(w, r, namespace, podName, "attach")
+}
+
+// proxyRemoteCommand resolves the worker cluster for podName and bridges an exec/attach SPDY
+// stream against that cluster's API server to the client: over raw SPDY when the client asks for
+// a SPDY upgrade, and otherwise over a websocket connection using the "v5.channel.k8s.io" framing
+// so standard terminal clients can drive it.
+func (p *ProxyServer) proxyRemoteCommand(w http.ResponseWriter, r *http.Request, namespace, podName, subResource string) {
+	containerName := r.URL.Query().Get("container")
+	if containerName == "" {
+		http.Error(w, "Container name must be provided as query parameter 'container'", http.StatusBadRequest)
+		return
+	}
+
+	command := r.URL.Query()["command"]
+	if subResource == "exec" && len(command) == 0 {
+		http.Error(w, "At least one 'command' query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	pipelineRunName := r.URL.Query().Get("pipelineRun")
+	if pipelineRunName == "" {
+		http.Error(w, "PipelineRun name must be provided as query parameter 'pipelineRun'", http.StatusBadRequest)
+		return
+	}
+
+	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	if err != nil {
+		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
+		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if workerCluster.State != "Admitted" {
+		http.Error(w, "PipelineRun not admitted to worker cluster", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("X-Worker-Cluster", workerCluster.Name)
+
+	// Require the pods/exec (or pods/attach) subresource explicitly, in addition to the base Pod
+	// access check shared with the status/logs endpoints.
+	subresourceAccess := p.authzHandler.CheckPodExecAccess
+	if subResource == "attach" {
+		subresourceAccess = p.authzHandler.CheckPodAttachAccess
+	}
+	if err := subresourceAccess(r.Context(), r, namespace, podName); err != nil {
+		metrics.AuthzDenials.WithLabelValues(subResource).Inc()
+		http.Error(w, fmt.Sprintf("Access denied: %v", err), http.StatusForbidden)
+		return
+	}
+
+	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	if err != nil {
+		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
+		return
+	}
+
+	tty := r.URL.Query().Get("tty") == "true"
+	stdin := r.URL.Query().Get("stdin") == "true"
+	stdout := r.URL.Query().Get("stdout") != "false"
+	stderr := r.URL.Query().Get("stderr") != "false" && !tty
+
+	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
+	req := kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource(subResource)
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: containerName,
+		Command:   command,
+		Stdin:     stdin,
+		Stdout:    stdout,
+		Stderr:    stderr,
+		TTY:       tty,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(workerConfig, "POST", req.URL())
+	if err != nil {
+		klog.Errorf("Failed to create SPDY executor against worker cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Failed to connect to worker cluster: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	if isSPDYUpgrade(r) {
+		p.proxyRemoteCommandSPDY(w, r, executor, workerCluster.Name, subResource, tty, stdin, stdout, stderr)
+		return
+	}
+	p.proxyRemoteCommandWebsocket(w, r, executor, workerCluster.Name, subResource, tty, stdin, stderr)
+}
+
+// proxyRemoteCommandWebsocket bridges executor, already dialed against the resolved worker
+// cluster, to the client over the "v5.channel.k8s.io" WebSocket subprotocol.
+func (p *ProxyServer) proxyRemoteCommandWebsocket(w http.ResponseWriter, r *http.Request, executor remotecommand.Executor, workerClusterName, subResource string, tty, stdin, stderr bool) {
+	upgradeHeader := http.Header{"X-Worker-Cluster": []string{workerClusterName}}
+	conn, err := execStreamUpgrader.Upgrade(w, r, upgradeHeader)
+	if err != nil {
+		klog.Errorf("Failed to upgrade to WebSocket for %s: %v", subResource, err)
+		return
+	}
+	defer conn.Close()
+
+	streams := newExecStreams(conn, tty)
+	defer streams.close()
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdout: streams.stdoutWriter(),
+	}
+	if stderr {
+		streamOpts.Stderr = streams.stderrWriter()
+	}
+	if stdin {
+		streamOpts.Stdin = streams.stdinReader
+	}
+	if tty {
+		streamOpts.Tty = true
+		streamOpts.TerminalSizeQueue = streams.resizeQueue
+	}
+
+	if err := executor.StreamWithContext(r.Context(), streamOpts); err != nil {
+		klog.Errorf("%s stream to worker cluster %s failed: %v", subResource, workerClusterName, err)
+		streams.writeError(err)
+	}
+}
+
+// proxyRemoteCommandSPDY bridges executor, already dialed against the resolved worker cluster, to
+// the client over a raw SPDY connection, hijacking the HTTP connection and negotiating one of the
+// "channel.k8s.io" subprotocols exactly as the Kubernetes API server itself does.
+func (p *ProxyServer) proxyRemoteCommandSPDY(w http.ResponseWriter, r *http.Request, executor remotecommand.Executor, workerClusterName, subResource string, tty, stdin, stdout, stderr bool) {
+	protocol, err := httpstream.Handshake(r, w, spdyRemoteCommandProtocols)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("SPDY protocol negotiation failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection does not support hijacking", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		klog.Errorf("Failed to hijack connection for %s: %v", subResource, err)
+		return
+	}
+
+	streams := newSPDYExecStreams(tty, stdin, stdout, stderr)
+	spdyConn, err := spdystream.NewServerConnection(conn, streams.newStreamHandler())
+	if err != nil {
+		klog.Errorf("Failed to establish SPDY connection for %s: %v", subResource, err)
+		conn.Close()
+		return
+	}
+	defer spdyConn.Close()
+
+	klog.V(4).Infof("Negotiated SPDY subprotocol %q for %s", protocol, subResource)
+
+	select {
+	case <-streams.ready:
+	case <-spdyConn.CloseChan():
+		return
+	case <-r.Context().Done():
+		return
+	}
+
+	streamOpts := remotecommand.StreamOptions{Stdout: streams.stdout}
+	if stderr {
+		streamOpts.Stderr = streams.stderr
+	}
+	if stdin {
+		streamOpts.Stdin = streams.stdin
+	}
+	if tty {
+		streamOpts.Tty = true
+		streamOpts.TerminalSizeQueue = streams.resizeQueue
+	}
+
+	if err := executor.StreamWithContext(r.Context(), streamOpts); err != nil {
+		klog.Errorf("%s stream to worker cluster %s failed: %v", subResource, workerClusterName, err)
+		streams.writeError(err)
+	}
+}
+
+// execStreams multiplexes stdin/stdout/stderr/resize for a single exec/attach session over one
+// websocket connection, and demultiplexes client frames back into a readable stdin stream and a
+// resize queue consumed by remotecommand.
+type execStreams struct {
+	conn        *websocket.Conn
+	writeMu     sync.Mutex
+	stdinReader *io.PipeReader
+	stdinWriter *io.PipeWriter
+	resizeQueue *wsTerminalSizeQueue
+	tty         bool
+}
+
+func newExecStreams(conn *websocket.Conn, tty bool) *execStreams {
+	stdinReader, stdinWriter := io.Pipe()
+	s := &execStreams{
+		conn:        conn,
+		stdinReader: stdinReader,
+		stdinWriter: stdinWriter,
+		resizeQueue: newWSTerminalSizeQueue(),
+		tty:         tty,
+	}
+	go s.readLoop()
+	return s
+}
+
+// readLoop demultiplexes incoming websocket frames into the stdin pipe (channelStdin) and the
+// resize queue (channelResize) for as long as the connection is open.
+func (s *execStreams) readLoop() {
+	defer s.stdinWriter.Close()
+	defer s.resizeQueue.Close()
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		switch remoteCommandChannel(data[0]) {
+		case channelStdin:
+			if _, err := s.stdinWriter.Write(data[1:]); err != nil {
+				return
+			}
+		case channelResize:
+			s.resizeQueue.Push(data[1:])
+		}
+	}
+}
+
+func (s *execStreams) stdoutWriter() *channelWriter {
+	return &channelWriter{streams: s, channel: channelStdout}
+}
+
+func (s *execStreams) stderrWriter() *channelWriter {
+	return &channelWriter{streams: s, channel: channelStderr}
+}
+
+func (s *execStreams) writeError(err error) {
+	s.write(channelError, []byte(err.Error()))
+}
+
+func (s *execStreams) write(channel remoteCommandChannel, p []byte) (int, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	frame := make([]byte, len(p)+1)
+	frame[0] = byte(channel)
+	copy(frame[1:], p)
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *execStreams) close() {
+	s.stdinReader.Close()
+}
+
+// channelWriter adapts a single multiplexed channel to io.Writer for use as remotecommand's
+// Stdout/Stderr.
+type channelWriter struct {
+	streams *execStreams
+	channel remoteCommandChannel
+}
+
+func (c *channelWriter) Write(p []byte) (int, error) {
+	return c.streams.write(c.channel, p)
+}
+
+// wsTerminalSizeQueue implements remotecommand.TerminalSizeQueue from resize frames received over
+// the exec websocket connection.
+type wsTerminalSizeQueue struct {
+	sizes chan remotecommand.TerminalSize
+}
+
+func newWSTerminalSizeQueue() *wsTerminalSizeQueue {
+	return &wsTerminalSizeQueue{sizes: make(chan remotecommand.TerminalSize, 1)}
+}
+
+// Push decodes a "{width}x{height}" resize payload and enqueues it, dropping malformed frames.
+func (q *wsTerminalSizeQueue) Push(payload []byte) {
+	var width, height uint16
+	if _, err := fmt.Sscanf(string(payload), "%dx%d", &width, &height); err != nil {
+		klog.V(4).Infof("Ignoring malformed resize frame %q: %v", payload, err)
+		return
+	}
+	select {
+	case q.sizes <- remotecommand.TerminalSize{Width: width, Height: height}:
+	default:
+		// Drop the resize event rather than blocking the read loop if the consumer is behind.
+	}
+}
+
+func (q *wsTerminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+func (q *wsTerminalSizeQueue) Close() {
+	close(q.sizes)
+}
+
+// spdyExecStreams collects the stdin/stdout/stderr/error/resize streams a raw-SPDY exec/attach
+// client opens on its hijacked httpstream.Connection, following the same per-stream-type layout
+// the Kubernetes API server itself expects, and exposes them as the io.Reader/io.Writer pair and
+// TerminalSizeQueue that remotecommand.StreamOptions wants.
+type spdyExecStreams struct {
+	mu                    sync.Mutex
+	stdin, stdout, stderr httpstream.Stream
+	errorStream           httpstream.Stream
+	resizeQueue           *spdyTerminalSizeQueue
+	ready                 chan struct{}
+	wantStdin, wantStdout bool
+	wantStderr            bool
+}
+
+func newSPDYExecStreams(tty, stdin, stdout, stderr bool) *spdyExecStreams {
+	return &spdyExecStreams{
+		resizeQueue: newSPDYTerminalSizeQueue(),
+		ready:       make(chan struct{}),
+		wantStdin:   stdin,
+		wantStdout:  stdout,
+		wantStderr:  stderr && !tty,
+	}
+}
+
+// newStreamHandler returns an httpstream.NewStreamHandler that files each incoming stream by its
+// StreamType header and signals ready once the error stream and every stream the client requested
+// have arrived.
+func (s *spdyExecStreams) newStreamHandler() httpstream.NewStreamHandler {
+	return func(stream httpstream.Stream, _ <-chan struct{}) error {
+		streamType := stream.Headers().Get(corev1.StreamType)
+
+		s.mu.Lock()
+		switch streamType {
+		case corev1.StreamTypeStdin:
+			s.stdin = stream
+		case corev1.StreamTypeStdout:
+			s.stdout = stream
+		case corev1.StreamTypeStderr:
+			s.stderr = stream
+		case corev1.StreamTypeError:
+			s.errorStream = stream
+		case corev1.StreamTypeResize:
+			go s.resizeQueue.readLoop(stream)
+		default:
+			s.mu.Unlock()
+			return fmt.Errorf("unknown stream type %q", streamType)
+		}
+		s.maybeReady()
+		s.mu.Unlock()
+		return nil
+	}
+}
+
+// maybeReady closes the ready channel once the error stream and every stream the client requested
+// have arrived. Callers must hold s.mu.
+func (s *spdyExecStreams) maybeReady() {
+	if s.errorStream == nil {
+		return
+	}
+	if s.wantStdin && s.stdin == nil {
+		return
+	}
+	if s.wantStdout && s.stdout == nil {
+		return
+	}
+	if s.wantStderr && s.stderr == nil {
+		return
+	}
+
+	select {
+	case <-s.ready:
+	default:
+		close(s.ready)
+	}
+}
+
+func (s *spdyExecStreams) writeError(err error) {
+	if s.errorStream != nil {
+		fmt.Fprint(s.errorStream, err.Error())
+	}
+}
+
+// spdyTerminalSizeQueue implements remotecommand.TerminalSizeQueue from JSON-encoded resize
+// messages received over a raw SPDY resize stream.
+type spdyTerminalSizeQueue struct {
+	sizes chan remotecommand.TerminalSize
+}
+
+func newSPDYTerminalSizeQueue() *spdyTerminalSizeQueue {
+	return &spdyTerminalSizeQueue{sizes: make(chan remotecommand.TerminalSize, 1)}
+}
+
+func (q *spdyTerminalSizeQueue) readLoop(stream httpstream.Stream) {
+	defer close(q.sizes)
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var size remotecommand.TerminalSize
+		if err := decoder.Decode(&size); err != nil {
+			return
+		}
+		select {
+		case q.sizes <- size:
+		default:
+			// Drop the resize event rather than blocking the read loop if the consumer is behind.
+		}
+	}
+}
+
+func (q *spdyTerminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// handlePodPortForward handles /pods/{pod}/portforward, relaying one or more ports from the
+// resolved worker cluster to the client. A client asking for a SPDY upgrade is relayed as a raw
+// SPDY "portforward.k8s.io" connection; any other client gets a multiplexed websocket connection
+// matching the port/stream layout of that protocol: each requested port gets a data channel and
+// an error channel, in the order the ports were requested.
+func (p *ProxyServer) handlePodPortForward(w http.ResponseWriter, r *http.Request, namespace, podName string) {
+	portParams := r.URL.Query()["ports"]
+	if len(portParams) == 0 {
+		http.Error(w, "At least one 'ports' query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ports := make([]uint16, 0, len(portParams))
+	for _, portParam := range portParams {
+		port, err := strconv.ParseUint(portParam, 10, 16)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid port %q: %v", portParam, err), http.StatusBadRequest)
+			return
+		}
+		ports = append(ports, uint16(port))
+	}
+
+	pipelineRunName := r.URL.Query().Get("pipelineRun")
+	if pipelineRunName == "" {
+		http.Error(w, "PipelineRun name must be provided as query parameter 'pipelineRun'", http.StatusBadRequest)
+		return
+	}
+
+	workerCluster, err := p.resolver.ResolveWorkerCluster(r.Context(), namespace, pipelineRunName)
+	if err != nil {
+		klog.Errorf("Failed to resolve worker cluster for PipelineRun %s: %v", pipelineRunName, err)
+		http.Error(w, fmt.Sprintf("Failed to resolve worker cluster: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if workerCluster.State != "Admitted" {
+		http.Error(w, "PipelineRun not admitted to worker cluster", http.StatusConflict)
+		return
+	}
+
+	if err := p.authzHandler.CheckPodPortForwardAccess(r.Context(), r, namespace, podName); err != nil {
+		metrics.AuthzDenials.WithLabelValues("portforward").Inc()
+		http.Error(w, fmt.Sprintf("Access denied: %v", err), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("X-Worker-Cluster", workerCluster.Name)
+
+	workerConfig, err := p.workerRegistry.GetConfig(workerCluster.Name)
+	if err != nil {
+		klog.Errorf("Failed to get worker config for cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Worker config not found: %v", err), http.StatusFailedDependency)
+		return
+	}
+
+	dialer, err := newPortForwardDialer(workerConfig, namespace, podName)
+	if err != nil {
+		klog.Errorf("Failed to build port-forward dialer for worker cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Failed to connect to worker cluster: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	streamConn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		klog.Errorf("Failed to dial port-forward on worker cluster %s: %v", workerCluster.Name, err)
+		http.Error(w, fmt.Sprintf("Failed to dial worker cluster: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer streamConn.Close()
+
+	if isSPDYUpgrade(r) {
+		p.proxyPortForwardSPDY(w, r, streamConn)
+		return
+	}
+
+	upgradeHeader := http.Header{"X-Worker-Cluster": []string{workerCluster.Name}}
+	conn, err := execStreamUpgrader.Upgrade(w, r, upgradeHeader)
+	if err != nil {
+		klog.Errorf("Failed to upgrade to WebSocket for portforward: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	relay := newPortForwardRelay(conn, streamConn, ports)
+	relay.run()
+}
+
+// proxyPortForwardSPDY hijacks the client connection and relays it as a raw SPDY
+// "portforward.k8s.io" connection against workerConn, opening a matching worker-side stream for
+// each stream the client opens and copying bytes between the two directly - no multiplexing
+// translation layer is needed since both ends already speak httpstream.
+func (p *ProxyServer) proxyPortForwardSPDY(w http.ResponseWriter, r *http.Request, workerConn httpstream.Connection) {
+	if _, err := httpstream.Handshake(r, w, []string{portforward.PortForwardProtocolV1Name}); err != nil {
+		http.Error(w, fmt.Sprintf("Port-forward protocol negotiation failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection does not support hijacking", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		klog.Errorf("Failed to hijack connection for portforward: %v", err)
+		return
+	}
+
+	clientConn, err := spdystream.NewServerConnection(conn, func(clientStream httpstream.Stream, _ <-chan struct{}) error {
+		go relayPortForwardStream(workerConn, clientStream)
+		return nil
+	})
+	if err != nil {
+		klog.Errorf("Failed to establish SPDY connection for portforward: %v", err)
+		conn.Close()
+		return
+	}
+	defer clientConn.Close()
+
+	<-clientConn.CloseChan()
+}
+
+// relayPortForwardStream opens the matching data/error stream on the worker cluster's SPDY
+// connection, mirroring clientStream's headers, and copies bytes until either side closes.
+func relayPortForwardStream(workerConn httpstream.Connection, clientStream httpstream.Stream) {
+	defer clientStream.Close()
+
+	workerStream, err := workerConn.CreateStream(clientStream.Headers())
+	if err != nil {
+		klog.Errorf("Failed to open worker port-forward stream: %v", err)
+		return
+	}
+	defer workerStream.Close()
+
+	if clientStream.Headers().Get(corev1.StreamType) == corev1.StreamTypeError {
+		io.Copy(clientStream, workerStream)
+		return
+	}
+
+	go io.Copy(workerStream, clientStream)
+	io.Copy(clientStream, workerStream)
+}
+
+// newPortForwardDialer builds an httpstream.Dialer that opens a SPDY connection to a pod's
+// portforward subresource on the resolved worker cluster's API server.
+func newPortForwardDialer(workerConfig *rest.Config, namespace, podName string) (httpstream.Dialer, error) {
+	kubeClient, err := kubernetes.NewForConfig(workerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kube client: %v", err)
+	}
+
+	req := kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward")
+
+	roundTripper, upgrader, err := spdy.RoundTripperFor(workerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SPDY round tripper: %v", err)
+	}
+
+	return spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, "POST", req.URL()), nil
+}
+
+// portForwardRelay shuttles bytes between a client websocket connection and the worker cluster's
+// SPDY port-forward streams. Each requested port gets a data channel and an error channel,
+// identified on the wire by a one-byte channel prefix in request order: port i's data channel is
+// 2*i, its error channel is 2*i+1.
+type portForwardRelay struct {
+	conn       *websocket.Conn
+	streamConn httpstream.Connection
+	ports      []uint16
+	writeMu    sync.Mutex
+}
+
+func newPortForwardRelay(conn *websocket.Conn, streamConn httpstream.Connection, ports []uint16) *portForwardRelay {
+	return &portForwardRelay{conn: conn, streamConn: streamConn, ports: ports}
+}
+
+func (relay *portForwardRelay) openStream(port uint16, requestID int, streamType string) (httpstream.Stream, error) {
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, streamType)
+	headers.Set(corev1.PortHeader, strconv.Itoa(int(port)))
+	headers.Set(corev1.PortForwardRequestIDHeader, strconv.Itoa(requestID))
+	return relay.streamConn.CreateStream(headers)
+}
+
+func (relay *portForwardRelay) run() {
+	dataStreams := make(map[byte]httpstream.Stream, len(relay.ports))
+	var allStreams []httpstream.Stream
+	var wg sync.WaitGroup
+
+	for i, port := range relay.ports {
+		errorStream, err := relay.openStream(port, i, corev1.StreamTypeError)
+		if err != nil {
+			klog.Errorf("Failed to open port-forward error stream for port %d: %v", port, err)
+			continue
+		}
+		dataStream, err := relay.openStream(port, i, corev1.StreamTypeData)
+		if err != nil {
+			klog.Errorf("Failed to open port-forward data stream for port %d: %v", port, err)
+			errorStream.Close()
+			continue
+		}
+
+		dataChannel := byte(i * 2)
+		errorChannel := byte(i*2 + 1)
+		dataStreams[dataChannel] = dataStream
+		allStreams = append(allStreams, dataStream, errorStream)
+
+		wg.Add(2)
+		go func(ch byte, s httpstream.Stream) {
+			defer wg.Done()
+			relay.pumpToClient(ch, s)
+		}(errorChannel, errorStream)
+		go func(ch byte, s httpstream.Stream) {
+			defer wg.Done()
+			relay.pumpToClient(ch, s)
+		}(dataChannel, dataStream)
+	}
+
+	relay.pumpFromClient(dataStreams)
+
+	// The client websocket is gone (or never sent any data), so the worker-side streams'
+	// pumpToClient goroutines would otherwise block forever in stream.Read(buf) on an idle
+	// forwarded connection. Close them all so wg.Wait() - and in turn handlePodPortForward's
+	// deferred streamConn.Close() - actually returns.
+	for _, s := range allStreams {
+		s.Close()
+	}
+	relay.streamConn.Close()
+
+	wg.Wait()
+}
+
+// pumpToClient copies bytes read from a worker-side stream to the client's websocket connection,
+// prefixed with the given channel byte.
+func (relay *portForwardRelay) pumpToClient(channel byte, stream httpstream.Stream) {
+	defer stream.Close()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stream.Read(buf)
+		if n > 0 {
+			frame := make([]byte, n+1)
+			frame[0] = channel
+			copy(frame[1:], buf[:n])
+
+			relay.writeMu.Lock()
+			writeErr := relay.conn.WriteMessage(websocket.BinaryMessage, frame)
+			relay.writeMu.Unlock()
+			if writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// pumpFromClient demultiplexes client websocket frames into the matching worker data stream until
+// the websocket connection closes.
+func (relay *portForwardRelay) pumpFromClient(dataStreams map[byte]httpstream.Stream) {
+	for {
+		_, frame, err := relay.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if len(frame) == 0 {
+			continue
+		}
+
+		stream, ok := dataStreams[frame[0]]
+		if !ok {
+			continue
+		}
+		if _, err := stream.Write(frame[1:]); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestIsSPDYUpgrade(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "spdy upgrade", header: "SPDY/3.1", want: true},
+		{name: "case insensitive", header: "spdy/3.1", want: true},
+		{name: "websocket upgrade", header: "websocket", want: false},
+		{name: "no upgrade header", header: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Upgrade", tt.header)
+			}
+			if got := isSPDYUpgrade(req); got != tt.want {
+				t.Errorf("isSPDYUpgrade() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSTerminalSizeQueue_PushAndNext(t *testing.T) {
+	q := newWSTerminalSizeQueue()
+	q.Push([]byte("80x24"))
+
+	size := q.Next()
+	if size == nil {
+		t.Fatal("expected a terminal size, got nil")
+	}
+	if size.Width != 80 || size.Height != 24 {
+		t.Errorf("got size %+v, want 80x24", size)
+	}
+}
+
+func TestWSTerminalSizeQueue_IgnoresMalformedPayload(t *testing.T) {
+	q := newWSTerminalSizeQueue()
+	q.Push([]byte("not-a-size"))
+	q.Push([]byte("80x24"))
+
+	size := q.Next()
+	if size == nil || size.Width != 80 || size.Height != 24 {
+		t.Errorf("expected the malformed frame to be dropped and the valid one kept, got %+v", size)
+	}
+}
+
+func TestWSTerminalSizeQueue_CloseYieldsNil(t *testing.T) {
+	q := newWSTerminalSizeQueue()
+	q.Close()
+
+	if size := q.Next(); size != nil {
+		t.Errorf("expected Next() to return nil after Close(), got %+v", size)
+	}
+}
+
+func TestExecStreams_WriteFramesChannelPrefix(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	serverDone := make(chan struct{})
+
+	var serverConn *websocket.Conn
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("server upgrade failed: %v", err)
+			return
+		}
+		serverConn = conn
+		close(serverDone)
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[len("http"):]
+	clientConn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("client dial failed: %v", err)
+	}
+	defer clientConn.Close()
+	<-serverDone
+	defer serverConn.Close()
+
+	streams := &execStreams{conn: serverConn}
+	if _, err := streams.stdoutWriter().Write([]byte("hello")); err != nil {
+		t.Fatalf("Write returned an error: %v", err)
+	}
+
+	_, frame, err := clientConn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read relayed frame: %v", err)
+	}
+
+	if len(frame) == 0 || remoteCommandChannel(frame[0]) != channelStdout {
+		t.Fatalf("expected frame prefixed with channelStdout, got %v", frame)
+	}
+	if string(frame[1:]) != "hello" {
+		t.Errorf("got payload %q, want %q", frame[1:], "hello")
+	}
+}
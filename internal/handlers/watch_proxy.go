@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+)
+
+// watchReconnectBackoff is how long the watch proxy waits before re-establishing a watch against
+// the worker cluster after the connection is lost.
+const watchReconnectBackoff = 2 * time.Second
+
+// watchEventEnvelope is the JSON shape written for each event on a watch stream, matching the
+// Kubernetes API server's "application/json;stream=watch" wire format.
+type watchEventEnvelope struct {
+	Type   watch.EventType `json:"type"`
+	Object interface{}     `json:"object"`
+}
+
+// watchSourceFunc starts a new watch against the worker cluster from the given resourceVersion.
+type watchSourceFunc func(resourceVersion string) (watch.Interface, error)
+
+// watchTaskRuns serves a Kubernetes-style watch stream of TaskRuns scoped to labelSelector,
+// reconnecting to the worker cluster and resuming from the last observed resourceVersion if the
+// underlying watch connection is lost.
+func (p *ProxyServer) watchTaskRuns(w http.ResponseWriter, r *http.Request, workerConfig *rest.Config, namespace, labelSelector string) {
+	tektonClient := tektonclient.NewForConfigOrDie(workerConfig)
+	allowBookmarks, timeoutSeconds := parseWatchOptions(r)
+
+	source := func(resourceVersion string) (watch.Interface, error) {
+		return tektonClient.TektonV1().TaskRuns(namespace).Watch(r.Context(), v1.ListOptions{
+			LabelSelector:       labelSelector,
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: allowBookmarks,
+			TimeoutSeconds:      timeoutSeconds,
+		})
+	}
+
+	p.runWatchProxy(w, r, source)
+}
+
+// watchPods serves a Kubernetes-style watch stream of Pods scoped to labelSelector, reconnecting
+// to the worker cluster and resuming from the last observed resourceVersion if the underlying
+// watch connection is lost.
+func (p *ProxyServer) watchPods(w http.ResponseWriter, r *http.Request, workerConfig *rest.Config, namespace, labelSelector string) {
+	kubeClient := kubernetes.NewForConfigOrDie(workerConfig)
+	allowBookmarks, timeoutSeconds := parseWatchOptions(r)
+
+	source := func(resourceVersion string) (watch.Interface, error) {
+		return kubeClient.CoreV1().Pods(namespace).Watch(r.Context(), v1.ListOptions{
+			LabelSelector:       labelSelector,
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: allowBookmarks,
+			TimeoutSeconds:      timeoutSeconds,
+		})
+	}
+
+	p.runWatchProxy(w, r, source)
+}
+
+// parseWatchOptions reads the allowWatchBookmarks and timeoutSeconds query parameters shared by
+// all watch endpoints.
+func parseWatchOptions(r *http.Request) (allowBookmarks bool, timeoutSeconds *int64) {
+	allowBookmarks = r.URL.Query().Get("allowWatchBookmarks") == "true"
+	if raw := r.URL.Query().Get("timeoutSeconds"); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			timeoutSeconds = &parsed
+		}
+	}
+	return allowBookmarks, timeoutSeconds
+}
+
+// runWatchProxy upgrades the response to a chunked "application/json;stream=watch" stream and
+// relays ADDED/MODIFIED/DELETED/BOOKMARK events from source, honoring the resourceVersion query
+// parameter as the starting point and transparently reconnecting (resuming from the last observed
+// resourceVersion) if the worker cluster connection drops.
+func (p *ProxyServer) runWatchProxy(w http.ResponseWriter, r *http.Request, source watchSourceFunc) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	resourceVersion := r.URL.Query().Get("resourceVersion")
+
+	// The worker closes its watch once timeoutSeconds elapses, which looks just like a lost
+	// connection; past this deadline the stream ends instead of reconnecting.
+	var deadline time.Time
+	if _, timeoutSeconds := parseWatchOptions(r); timeoutSeconds != nil {
+		deadline = time.Now().Add(time.Duration(*timeoutSeconds) * time.Second)
+	}
+
+	watcher, err := source(resourceVersion)
+	if err != nil {
+		klog.Errorf("Failed to start watch at resourceVersion %s: %v", resourceVersion, err)
+		http.Error(w, fmt.Sprintf("Failed to start watch: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;stream=watch")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+
+	for {
+		lost := streamWatchEvents(r, watcher, encoder, flusher, &resourceVersion)
+		watcher.Stop()
+
+		if !lost {
+			// Client disconnected.
+			return
+		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			// The requested timeoutSeconds has elapsed.
+			return
+		}
+
+		klog.Warningf("Watch connection to worker cluster lost at resourceVersion %s, reconnecting", resourceVersion)
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(watchReconnectBackoff):
+		}
+
+		watcher, err = source(resourceVersion)
+		if err != nil {
+			klog.Errorf("Failed to reconnect watch at resourceVersion %s: %v", resourceVersion, err)
+			return
+		}
+	}
+}
+
+// streamWatchEvents relays events from watcher to the client until the client disconnects (returns
+// false, no reconnect needed) or the worker cluster's watch channel closes (returns true, caller
+// should reconnect). It tracks the most recently observed resourceVersion in *resourceVersion so a
+// reconnect can resume from where the stream left off.
+func streamWatchEvents(r *http.Request, watcher watch.Interface, encoder *json.Encoder, flusher http.Flusher, resourceVersion *string) bool {
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return true
+			}
+
+			if accessor, err := apimeta.Accessor(event.Object); err == nil {
+				*resourceVersion = accessor.GetResourceVersion()
+			}
+
+			if err := encoder.Encode(&watchEventEnvelope{Type: event.Type, Object: event.Object}); err != nil {
+				klog.Errorf("Failed to encode watch event: %v", err)
+				return false
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return false
+		}
+	}
+}
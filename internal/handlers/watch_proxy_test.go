@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestParseWatchOptions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?allowWatchBookmarks=true&timeoutSeconds=30", nil)
+
+	allowBookmarks, timeoutSeconds := parseWatchOptions(req)
+
+	if !allowBookmarks {
+		t.Error("expected allowWatchBookmarks=true to parse as true")
+	}
+	if timeoutSeconds == nil || *timeoutSeconds != 30 {
+		t.Errorf("got timeoutSeconds %v, want 30", timeoutSeconds)
+	}
+}
+
+func TestParseWatchOptions_Defaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	allowBookmarks, timeoutSeconds := parseWatchOptions(req)
+
+	if allowBookmarks {
+		t.Error("expected allowWatchBookmarks to default to false")
+	}
+	if timeoutSeconds != nil {
+		t.Errorf("expected timeoutSeconds to default to nil, got %v", timeoutSeconds)
+	}
+}
+
+func TestStreamWatchEvents_RelaysEventsAndTracksResourceVersion(t *testing.T) {
+	fakeWatch := watch.NewFake()
+	rr := httptest.NewRecorder()
+	encoder := json.NewEncoder(rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	done := make(chan bool)
+	var resourceVersion string
+	go func() {
+		done <- streamWatchEvents(req, fakeWatch, encoder, rr, &resourceVersion)
+	}()
+
+	pod := &corev1.Pod{}
+	pod.Name = "p1"
+	pod.ResourceVersion = "42"
+	fakeWatch.Add(pod)
+	fakeWatch.Stop()
+
+	lost := <-done
+	if !lost {
+		t.Error("expected streamWatchEvents to report the channel closing as a lost connection")
+	}
+	if resourceVersion != "42" {
+		t.Errorf("got resourceVersion %q, want %q", resourceVersion, "42")
+	}
+
+	scanner := bufio.NewScanner(rr.Body)
+	if !scanner.Scan() {
+		t.Fatal("expected at least one encoded watch event in the response body")
+	}
+
+	var envelope watchEventEnvelope
+	if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil {
+		t.Fatalf("failed to decode watch event envelope: %v", err)
+	}
+	if envelope.Type != watch.Added {
+		t.Errorf("got event type %q, want %q", envelope.Type, watch.Added)
+	}
+}
+
+func TestStreamWatchEvents_ClientDisconnectReturnsFalse(t *testing.T) {
+	fakeWatch := watch.NewFake()
+	rr := httptest.NewRecorder()
+	encoder := json.NewEncoder(rr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	var resourceVersion string
+	done := make(chan bool)
+	go func() {
+		done <- streamWatchEvents(req, fakeWatch, encoder, rr, &resourceVersion)
+	}()
+
+	cancel()
+
+	if lost := <-done; lost {
+		t.Error("expected a client disconnect to report lost=false (no reconnect needed)")
+	}
+}
@@ -0,0 +1,55 @@
+// Package metrics holds the Prometheus collectors exposed by the proxy's /metrics endpoint.
+// Collectors are package-level so any package can record against them without needing a handle
+// threaded through constructors.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// RequestDuration tracks how long each handler takes to serve a request, labeled by the worker
+	// cluster the handler set in its X-Worker-Cluster response header (or "unknown" if it set none)
+	// and the HTTP status code written.
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "proxy_request_duration_seconds",
+		Help:    "Duration of proxy HTTP requests in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"handler", "worker_cluster", "code"})
+
+	// AuthzDenials counts requests rejected by an AuthzHandler check, labeled by the handler that
+	// performed the check.
+	AuthzDenials = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "proxy_authz_denials_total",
+		Help: "Total number of requests denied by an authorization check.",
+	}, []string{"handler"})
+
+	// ActiveLogStreams tracks the number of currently open WebSocket/SSE log streams, single- and
+	// multi-container combined.
+	ActiveLogStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "proxy_active_log_streams",
+		Help: "Number of currently open log streaming connections.",
+	})
+
+	// KnownWorkerClusters tracks the number of worker clusters currently known to the
+	// WorkerConfigRegistry.
+	KnownWorkerClusters = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "proxy_known_worker_clusters",
+		Help: "Number of worker clusters currently known to the proxy.",
+	})
+
+	// MultiKueueClusterEvents counts MultiKueueCluster watch events observed by the
+	// WorkerConfigRegistry, labeled by event type.
+	MultiKueueClusterEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "proxy_multikueue_cluster_events_total",
+		Help: "Total number of MultiKueueCluster watch events observed, by event type.",
+	}, []string{"event_type"})
+
+	// AuthzCacheResults counts authz cache lookups, labeled by which cache was consulted
+	// ("identity" or "sar") and whether it was a hit or a miss.
+	AuthzCacheResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "proxy_authz_cache_results_total",
+		Help: "Total number of authz cache lookups, labeled by cache and hit/miss.",
+	}, []string{"cache", "result"})
+)
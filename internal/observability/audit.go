@@ -0,0 +1,79 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// AuditEvent is a single structured audit log entry describing one authorization decision and,
+// once the request finishes, the worker cluster it was ultimately dispatched to.
+type AuditEvent struct {
+	Time      time.Time `json:"time"`
+	User      string    `json:"user,omitempty"`
+	Verb      string    `json:"verb,omitempty"`
+	Resource  string    `json:"resource,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Decision  string    `json:"decision,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	LatencyMS float64   `json:"latencyMs"`
+}
+
+// AuditLogger writes AuditEvents to a sink as newline-delimited JSON, matching the convention
+// most log-aggregation pipelines expect. It is safe for concurrent use.
+type AuditLogger struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewAuditLogger creates an AuditLogger that writes to out (for example an os.File opened against
+// a configured audit log path, or os.Stdout for the common case of letting the cluster's log
+// collector pick events up from the container's stdout).
+func NewAuditLogger(out io.Writer) *AuditLogger {
+	return &AuditLogger{out: out}
+}
+
+// Log writes event to the sink as a single JSON line, timestamping it with the current time.
+func (l *AuditLogger) Log(event AuditEvent) {
+	event.Time = time.Now()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("Failed to marshal audit event: %v", err)
+		return
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.out.Write(data); err != nil {
+		klog.Errorf("Failed to write audit event: %v", err)
+	}
+}
+
+// auditRecorderKey is the context key under which WithAuditRecorder stashes an in-flight
+// AuditEvent.
+type auditRecorderKey struct{}
+
+// WithAuditRecorder attaches a mutable AuditEvent to ctx that cooperating packages fill in as the
+// request is served: the authz package records the caller, the requested access, and the
+// decision at check time, and the handlers package records the resolved worker cluster and
+// latency once the request completes. This lets a single audit event be logged at the end of the
+// request even though its fields become known at different points in the call stack.
+func WithAuditRecorder(ctx context.Context) (context.Context, *AuditEvent) {
+	event := &AuditEvent{}
+	return context.WithValue(ctx, auditRecorderKey{}, event), event
+}
+
+// AuditRecorderFrom returns the AuditEvent attached to ctx by WithAuditRecorder, if any, so a
+// package deeper in the call stack can fill in the fields it alone knows about.
+func AuditRecorderFrom(ctx context.Context) (*AuditEvent, bool) {
+	event, ok := ctx.Value(auditRecorderKey{}).(*AuditEvent)
+	return event, ok
+}
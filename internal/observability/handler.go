@@ -0,0 +1,14 @@
+package observability
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// WrapHandler wraps h with otelhttp instrumentation so every request proxied through it carries
+// (or starts) a trace that continues through the resolver, registry, and outbound worker-cluster
+// client calls internal/tracing.Tracer() spans from.
+func WrapHandler(h http.Handler) http.Handler {
+	return otelhttp.NewHandler(h, ServiceName)
+}
@@ -0,0 +1,20 @@
+package observability
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewMetricsServer returns an *http.Server serving the Prometheus collectors registered in
+// internal/metrics on addr, separate from the proxy's main request-serving port so scraping
+// /metrics never competes with proxied traffic for the same listener.
+func NewMetricsServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+}
@@ -0,0 +1,60 @@
+// Package observability wires up the proxy's cross-cutting observability surface: the OTel
+// tracer provider, the separately-served Prometheus metrics endpoint, and structured audit
+// logging. It exists alongside the narrower internal/tracing and internal/metrics packages so
+// that handlers and authz backends can keep depending on just the tracer/collectors they need,
+// while main.go has one place to initialize and wire all three.
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// ServiceName identifies this proxy's spans and resource attributes to whatever OTel collector
+// the operator points TracingOTLPEndpoint at. It matches the tracer name internal/tracing uses.
+const ServiceName = "github.com/khrm/proxy-aae"
+
+// InitTracerProvider dials otlpEndpoint over OTLP/gRPC and installs the resulting TracerProvider
+// as the global one that internal/tracing.Tracer() and otelhttp read from, along with the W3C
+// trace-context propagator otelhttp uses to continue a trace from an incoming traceparent header.
+// Requests to worker clusters are not instrumented, so no traceparent header is sent on the
+// hub-to-worker hop; those calls show up only as the proxy's own spans. It returns a shutdown
+// func that flushes and closes the exporter; callers should defer it. An empty otlpEndpoint disables tracing: the
+// global no-op TracerProvider is left in place and shutdown is a no-op.
+func InitTracerProvider(ctx context.Context, otlpEndpoint string) (shutdown func(context.Context) error, err error) {
+	if otlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(otlpEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %v", err)
+	}
+
+	res, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewSchemaless(
+		semconv.ServiceNameKey.String(ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+)
+
+// workerClusterGVR identifies the WorkerCluster CRD. There is no generated clientset for it, so
+// WorkerClusterCRDSource talks to it through the dynamic client like any other unstructured
+// resource.
+var workerClusterGVR = schema.GroupVersionResource{
+	Group:    "proxy.tekton.dev",
+	Version:  "v1alpha1",
+	Resource: "workerclusters",
+}
+
+// WorkerClusterCRDSource discovers worker clusters from WorkerCluster custom resources. Each
+// WorkerCluster names an API server directly and one of three auth strategies, rather than
+// pointing at a single kubeconfig Secret the way MultiKueueSecretSource does.
+type WorkerClusterCRDSource struct {
+	dynamicClient dynamic.Interface
+	kubeClient    kubernetes.Interface
+	namespace     string
+}
+
+// NewWorkerClusterCRDSource creates a WorkerClusterCRDSource. namespace scopes which namespace's
+// WorkerCluster resources and auth secrets are read.
+func NewWorkerClusterCRDSource(dynamicClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string) *WorkerClusterCRDSource {
+	return &WorkerClusterCRDSource{dynamicClient: dynamicClient, kubeClient: kubeClient, namespace: namespace}
+}
+
+// Load lists every WorkerCluster and builds a rest.Config for each from its spec'd API server URL,
+// CA bundle, and auth strategy.
+func (s *WorkerClusterCRDSource) Load(ctx context.Context) (map[string]*rest.Config, error) {
+	list, err := s.dynamicClient.Resource(workerClusterGVR).Namespace(s.namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list WorkerClusters: %v", err)
+	}
+
+	configs := make(map[string]*rest.Config, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		clusterName := item.GetName()
+
+		restConfig, err := s.buildConfig(ctx, item)
+		if err != nil {
+			klog.Errorf("Failed to build config for WorkerCluster %s: %v", clusterName, err)
+			continue
+		}
+
+		configs[clusterName] = restConfig
+		klog.Infof("Loaded worker config for cluster: %s (WorkerCluster CRD)", clusterName)
+	}
+
+	return configs, nil
+}
+
+// Watch watches WorkerCluster resources and invokes onChange on every add, modification, or
+// removal.
+func (s *WorkerClusterCRDSource) Watch(ctx context.Context, onChange func()) {
+	go func() {
+		for {
+			watcher, err := s.dynamicClient.Resource(workerClusterGVR).Namespace(s.namespace).Watch(ctx, v1.ListOptions{})
+			if err != nil {
+				klog.Errorf("Failed to watch WorkerClusters: %v", err)
+				return
+			}
+
+			lost := s.consumeWorkerClusterEvents(ctx, watcher, onChange)
+			watcher.Stop()
+			if !lost {
+				return
+			}
+
+			klog.Warningf("Watch connection for WorkerClusters lost, reconnecting")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchReconnectBackoff):
+			}
+		}
+	}()
+}
+
+// consumeWorkerClusterEvents relays WorkerCluster watch events to onChange until ctx is done
+// (returns false, no reconnect needed) or the watch channel closes (returns true, caller should
+// reconnect).
+func (s *WorkerClusterCRDSource) consumeWorkerClusterEvents(ctx context.Context, watcher watch.Interface, onChange func()) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return true
+			}
+			switch event.Type {
+			case "ADDED", "MODIFIED", "DELETED":
+				onChange()
+			}
+		}
+	}
+}
+
+// buildConfig translates a WorkerCluster's spec into a rest.Config.
+func (s *WorkerClusterCRDSource) buildConfig(ctx context.Context, item *unstructured.Unstructured) (*rest.Config, error) {
+	apiServerURL, found, err := unstructured.NestedString(item.Object, "spec", "apiServerURL")
+	if err != nil || !found || apiServerURL == "" {
+		return nil, fmt.Errorf("spec.apiServerURL is required")
+	}
+
+	restConfig := &rest.Config{Host: apiServerURL}
+
+	if caSecretName, found, _ := unstructured.NestedString(item.Object, "spec", "caBundle", "secretRef", "name"); found && caSecretName != "" {
+		caKey, _, _ := unstructured.NestedString(item.Object, "spec", "caBundle", "secretRef", "key")
+		if caKey == "" {
+			caKey = "ca.crt"
+		}
+		secret, err := s.kubeClient.CoreV1().Secrets(s.namespace).Get(ctx, caSecretName, v1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CA secret %s: %v", caSecretName, err)
+		}
+		restConfig.TLSClientConfig.CAData = secret.Data[caKey]
+	}
+
+	authStrategy, _, _ := unstructured.NestedString(item.Object, "spec", "auth", "strategy")
+	switch authStrategy {
+	case "bearer":
+		secretName, _, _ := unstructured.NestedString(item.Object, "spec", "auth", "bearer", "secretRef", "name")
+		key, _, _ := unstructured.NestedString(item.Object, "spec", "auth", "bearer", "secretRef", "key")
+		if key == "" {
+			key = "token"
+		}
+		secret, err := s.kubeClient.CoreV1().Secrets(s.namespace).Get(ctx, secretName, v1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bearer token secret %s: %v", secretName, err)
+		}
+		restConfig.BearerToken = string(secret.Data[key])
+
+	case "clientCert":
+		secretName, _, _ := unstructured.NestedString(item.Object, "spec", "auth", "clientCert", "secretRef", "name")
+		secret, err := s.kubeClient.CoreV1().Secrets(s.namespace).Get(ctx, secretName, v1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client cert secret %s: %v", secretName, err)
+		}
+		restConfig.TLSClientConfig.CertData = secret.Data["tls.crt"]
+		restConfig.TLSClientConfig.KeyData = secret.Data["tls.key"]
+
+	case "exec":
+		command, _, _ := unstructured.NestedString(item.Object, "spec", "auth", "exec", "command")
+		if command == "" {
+			return nil, fmt.Errorf("spec.auth.exec.command is required")
+		}
+		args, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "auth", "exec", "args")
+		apiVersion, _, _ := unstructured.NestedString(item.Object, "spec", "auth", "exec", "apiVersion")
+		if apiVersion == "" {
+			apiVersion = "client.authentication.k8s.io/v1"
+		}
+		restConfig.ExecProvider = &clientcmdapi.ExecConfig{
+			Command:    command,
+			Args:       args,
+			APIVersion: apiVersion,
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported auth strategy: %q", authStrategy)
+	}
+
+	return restConfig, nil
+}
+
+var _ CredentialSource = (*WorkerClusterCRDSource)(nil)
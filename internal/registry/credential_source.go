@@ -0,0 +1,154 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/khrm/proxy-aae/internal/config"
+	"github.com/khrm/proxy-aae/internal/metrics"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	kueueclient "sigs.k8s.io/kueue/client-go/clientset/versioned"
+)
+
+// watchReconnectBackoff is how long a CredentialSource's Watch waits before re-establishing a
+// watch against the hub cluster after the connection is lost, mirroring the handlers package's
+// watch proxy reconnect logic.
+const watchReconnectBackoff = 2 * time.Second
+
+// MultiKueueClusterAnnotation is the annotation used to link secrets to MultiKueueCluster
+const MultiKueueClusterAnnotation = "kueue.x-k8s.io/multikueue-cluster"
+
+// CredentialSource supplies worker cluster rest.Configs and notifies the registry whenever they
+// change, so WorkerConfigRegistry can hot-reload worker connections without being tied to any one
+// way of storing worker credentials.
+type CredentialSource interface {
+	// Load returns the current set of worker cluster configs, keyed by cluster name.
+	Load(ctx context.Context) (map[string]*rest.Config, error)
+
+	// Watch runs until ctx is done, calling onChange whenever it observes the underlying
+	// credentials change so the caller can re-Load. Watch must not block forever on the calling
+	// goroutine: implementations that need a long-running watch loop should start it in their own
+	// goroutine and return once that loop is scheduled.
+	Watch(ctx context.Context, onChange func())
+}
+
+// MultiKueueSecretSource is the original CredentialSource: it discovers worker clusters from
+// MultiKueueCluster resources on the hub cluster and loads their kubeconfig from a Secret in a
+// single configured namespace.
+type MultiKueueSecretSource struct {
+	kubeClient  kubernetes.Interface
+	kueueClient kueueclient.Interface
+	config      *config.Config
+}
+
+// NewMultiKueueSecretSource creates a MultiKueueSecretSource.
+func NewMultiKueueSecretSource(kubeClient kubernetes.Interface, kueueClient kueueclient.Interface, cfg *config.Config) *MultiKueueSecretSource {
+	return &MultiKueueSecretSource{kubeClient: kubeClient, kueueClient: kueueClient, config: cfg}
+}
+
+// Load lists every MultiKueueCluster and resolves each one's kubeconfig Secret into a rest.Config.
+func (s *MultiKueueSecretSource) Load(ctx context.Context) (map[string]*rest.Config, error) {
+	clusters, err := s.kueueClient.KueueV1beta1().MultiKueueClusters().List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MultiKueueClusters: %v", err)
+	}
+
+	configs := make(map[string]*rest.Config, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		clusterName := cluster.Name
+
+		if cluster.Spec.KubeConfig.LocationType != "Secret" {
+			klog.Warningf("MultiKueueCluster %s has unsupported location type: %s", clusterName, cluster.Spec.KubeConfig.LocationType)
+			continue
+		}
+
+		secretName := cluster.Spec.KubeConfig.Location
+		if secretName == "" {
+			klog.Warningf("MultiKueueCluster %s has empty secret location", clusterName)
+			continue
+		}
+
+		secret, err := s.kubeClient.CoreV1().Secrets(s.config.WorkersSecretNamespace).Get(ctx, secretName, v1.GetOptions{})
+		if err != nil {
+			klog.Errorf("Failed to get secret %s for cluster %s: %v", secretName, clusterName, err)
+			continue
+		}
+
+		kubeconfigData, exists := secret.Data["kubeconfig"]
+		if !exists {
+			klog.Warningf("Secret %s does not contain kubeconfig data", secretName)
+			continue
+		}
+
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigData)
+		if err != nil {
+			klog.Errorf("Failed to parse kubeconfig for cluster %s: %v", clusterName, err)
+			continue
+		}
+
+		configs[clusterName] = restConfig
+		klog.Infof("Loaded worker config for cluster: %s (secret: %s)", clusterName, secretName)
+	}
+
+	return configs, nil
+}
+
+// Watch watches MultiKueueCluster resources on the hub cluster and invokes onChange on every add,
+// modification, or removal.
+func (s *MultiKueueSecretSource) Watch(ctx context.Context, onChange func()) {
+	go func() {
+		for {
+			watcher, err := s.kueueClient.KueueV1beta1().MultiKueueClusters().Watch(ctx, v1.ListOptions{})
+			if err != nil {
+				klog.Errorf("Failed to watch MultiKueueClusters: %v", err)
+				return
+			}
+
+			lost := s.consumeMultiKueueClusterEvents(ctx, watcher, onChange)
+			watcher.Stop()
+			if !lost {
+				return
+			}
+
+			klog.Warningf("Watch connection for MultiKueueClusters lost, reconnecting")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchReconnectBackoff):
+			}
+		}
+	}()
+}
+
+// consumeMultiKueueClusterEvents relays MultiKueueCluster watch events to onChange until ctx is
+// done (returns false, no reconnect needed) or the watch channel closes (returns true, caller
+// should reconnect).
+func (s *MultiKueueSecretSource) consumeMultiKueueClusterEvents(ctx context.Context, watcher watch.Interface, onChange func()) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return true
+			}
+			switch event.Type {
+			case "ADDED", "MODIFIED", "DELETED":
+				metrics.MultiKueueClusterEvents.WithLabelValues(strings.ToLower(string(event.Type))).Inc()
+				onChange()
+			}
+		}
+	}
+}
+
+var _ CredentialSource = (*MultiKueueSecretSource)(nil)
+var _ = (*kueuev1beta1.MultiKueueCluster)(nil) // referenced only via the generated clientset above
@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/khrm/proxy-aae/internal/config"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+const testKubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: worker
+  cluster:
+    server: https://worker.example.com
+current-context: worker
+contexts:
+- name: worker
+  context:
+    cluster: worker
+    user: worker
+users:
+- name: worker
+  user:
+    token: worker-token
+`
+
+func TestMultiKueueSecretSource_Load(t *testing.T) {
+	cfg := &config.Config{WorkersSecretNamespace: "kueue-system"}
+
+	kubeClient := kubefake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "worker-kubeconfig", Namespace: "kueue-system"},
+		Data: map[string][]byte{
+			"kubeconfig": []byte(testKubeconfig),
+		},
+	})
+
+	kueueClient := kueuefake.NewSimpleClientset(&kueuev1beta1.MultiKueueCluster{
+		ObjectMeta: v1.ObjectMeta{Name: "worker-1"},
+		Spec: kueuev1beta1.MultiKueueClusterSpec{
+			KubeConfig: kueuev1beta1.KubeConfig{
+				LocationType: "Secret",
+				Location:     "worker-kubeconfig",
+			},
+		},
+	})
+
+	source := NewMultiKueueSecretSource(kubeClient, kueueClient, cfg)
+
+	configs, err := source.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	restConfig, ok := configs["worker-1"]
+	if !ok {
+		t.Fatalf("expected a config for cluster worker-1, got %v", configs)
+	}
+	if restConfig.Host != "https://worker.example.com" {
+		t.Errorf("got host %q, want %q", restConfig.Host, "https://worker.example.com")
+	}
+}
+
+func TestMultiKueueSecretSource_Load_SkipsMissingSecret(t *testing.T) {
+	cfg := &config.Config{WorkersSecretNamespace: "kueue-system"}
+
+	kubeClient := kubefake.NewSimpleClientset()
+	kueueClient := kueuefake.NewSimpleClientset(&kueuev1beta1.MultiKueueCluster{
+		ObjectMeta: v1.ObjectMeta{Name: "worker-1"},
+		Spec: kueuev1beta1.MultiKueueClusterSpec{
+			KubeConfig: kueuev1beta1.KubeConfig{
+				LocationType: "Secret",
+				Location:     "missing-secret",
+			},
+		},
+	})
+
+	source := NewMultiKueueSecretSource(kubeClient, kueueClient, cfg)
+
+	configs, err := source.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+	if len(configs) != 0 {
+		t.Fatalf("expected no configs when the referenced secret is missing, got %v", configs)
+	}
+}
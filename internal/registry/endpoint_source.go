@@ -0,0 +1,164 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+)
+
+// saTokenFile is the path the proxy's own service account token is projected to in-cluster.
+const saTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// defaultRefreshBuffer is how long before a kubeconfig's reported expiry EndpointCredentialSource
+// refreshes it, absent an explicit RefreshBuffer.
+const defaultRefreshBuffer = 1 * time.Minute
+
+// endpointResponse is the JSON body returned by the credential endpoint.
+type endpointResponse struct {
+	// Clusters maps cluster name to a short-lived kubeconfig for that cluster.
+	Clusters map[string]string `json:"clusters"`
+
+	// ExpiresAt is when the returned kubeconfigs stop being valid.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// EndpointCredentialSource fetches worker cluster kubeconfigs from an HTTP endpoint by POSTing
+// the proxy's own service account token, refreshing the result shortly before it expires. This
+// mirrors the auth-keys endpoint pattern used by the Tailscale operator's containerboot.
+type EndpointCredentialSource struct {
+	url           string
+	refreshBuffer time.Duration
+	httpClient    *http.Client
+	tokenFile     string
+}
+
+// NewEndpointCredentialSource creates an EndpointCredentialSource. A non-positive refreshBuffer
+// falls back to defaultRefreshBuffer.
+func NewEndpointCredentialSource(url string, refreshBuffer time.Duration) *EndpointCredentialSource {
+	if refreshBuffer <= 0 {
+		refreshBuffer = defaultRefreshBuffer
+	}
+	return &EndpointCredentialSource{
+		url:           url,
+		refreshBuffer: refreshBuffer,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		tokenFile:     saTokenFile,
+	}
+}
+
+// Load fetches the current set of worker kubeconfigs from the endpoint.
+func (s *EndpointCredentialSource) Load(ctx context.Context) (map[string]*rest.Config, error) {
+	resp, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]*rest.Config, len(resp.Clusters))
+	for clusterName, kubeconfig := range resp.Clusters {
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+		if err != nil {
+			klog.Errorf("Failed to parse kubeconfig returned for cluster %s: %v", clusterName, err)
+			continue
+		}
+		configs[clusterName] = restConfig
+	}
+
+	return configs, nil
+}
+
+// Watch schedules a refresh shortly before the most recently fetched credentials expire, calling
+// onChange after every successful refresh so the registry reloads. It keeps refreshing on the same
+// schedule until ctx is done.
+//
+// The delay before the very first refresh is computed from an ExpiresAt fetched here, rather than
+// defaulting to refreshBuffer: the registry's own initial Load happens independently of Watch, so
+// Watch has no other way to learn how long the credentials it's scheduling around already have
+// left. Defaulting to refreshBuffer let credentials whose TTL is shorter than refreshBuffer - the
+// short-lived kubeconfig case this source exists for - expire before the first scheduled refresh.
+func (s *EndpointCredentialSource) Watch(ctx context.Context, onChange func()) {
+	go func() {
+		delay := s.refreshBuffer
+		if resp, err := s.fetch(ctx); err != nil {
+			klog.Errorf("Failed to fetch initial credential expiry from endpoint %s: %v", s.url, err)
+		} else {
+			delay = s.nextRefreshDelay(resp.ExpiresAt)
+		}
+
+		for {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			resp, err := s.fetch(ctx)
+			if err != nil {
+				klog.Errorf("Failed to refresh credentials from endpoint %s: %v", s.url, err)
+				delay = s.refreshBuffer
+				continue
+			}
+
+			onChange()
+			delay = s.nextRefreshDelay(resp.ExpiresAt)
+		}
+	}()
+}
+
+// nextRefreshDelay returns how long to wait before refreshing again: refreshBuffer before
+// expiresAt, or refreshBuffer itself when expiresAt is already less than refreshBuffer away, so a
+// clock skew or an endpoint returning an already-close expiry doesn't busy-loop refreshes.
+func (s *EndpointCredentialSource) nextRefreshDelay(expiresAt time.Time) time.Duration {
+	untilExpiry := time.Until(expiresAt) - s.refreshBuffer
+	if untilExpiry <= 0 {
+		untilExpiry = s.refreshBuffer
+	}
+	return untilExpiry
+}
+
+// fetch POSTs the proxy's service account token to the endpoint and decodes the response.
+func (s *EndpointCredentialSource) fetch(ctx context.Context) (*endpointResponse, error) {
+	token, err := os.ReadFile(s.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]string{"token": string(token)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build credential request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call credential endpoint: %v", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("credential endpoint returned status %d", httpResp.StatusCode)
+	}
+
+	var resp endpointResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode credential response: %v", err)
+	}
+
+	return &resp, nil
+}
+
+var _ CredentialSource = (*EndpointCredentialSource)(nil)
@@ -0,0 +1,69 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestEndpointSource(t *testing.T, handler http.HandlerFunc) *EndpointCredentialSource {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("sa-token"), 0600); err != nil {
+		t.Fatalf("failed to write fake service account token: %v", err)
+	}
+
+	source := NewEndpointCredentialSource(server.URL, time.Minute)
+	source.tokenFile = tokenFile
+	return source
+}
+
+func TestEndpointCredentialSource_Load(t *testing.T) {
+	source := newTestEndpointSource(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(endpointResponse{
+			Clusters: map[string]string{
+				"worker-1": testKubeconfig,
+			},
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+	})
+
+	configs, err := source.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	restConfig, ok := configs["worker-1"]
+	if !ok {
+		t.Fatalf("expected a config for cluster worker-1, got %v", configs)
+	}
+	if restConfig.Host != "https://worker.example.com" {
+		t.Errorf("got host %q, want %q", restConfig.Host, "https://worker.example.com")
+	}
+}
+
+func TestEndpointCredentialSource_Load_NonOKStatus(t *testing.T) {
+	source := newTestEndpointSource(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	if _, err := source.Load(context.Background()); err == nil {
+		t.Fatal("expected an error for a non-200 response")
+	}
+}
+
+func TestNewEndpointCredentialSource_DefaultsRefreshBuffer(t *testing.T) {
+	source := NewEndpointCredentialSource("https://example.com", 0)
+	if source.refreshBuffer != defaultRefreshBuffer {
+		t.Errorf("got refresh buffer %v, want %v", source.refreshBuffer, defaultRefreshBuffer)
+	}
+}
@@ -4,43 +4,70 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
-	"github.com/khrm/proxy-aae/internal/config"
-	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
-	kueueclient "sigs.k8s.io/kueue/client-go/clientset/versioned"
+	"github.com/khrm/proxy-aae/internal/metrics"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	tektoninformers "github.com/tektoncd/pipeline/pkg/client/informers/externalversions"
+	tektonlisters "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1"
 )
 
-const (
-	// MultiKueueClusterAnnotation is the annotation used to link secrets to MultiKueueCluster
-	MultiKueueClusterAnnotation = "kueue.x-k8s.io/multikueue-cluster"
-)
+// workerResyncPeriod controls how often the per-worker informers resync
+const workerResyncPeriod = 10 * time.Minute
+
+// WorkerCache holds the informer-backed listers for a single worker cluster
+type WorkerCache struct {
+	PodLister     corelisters.PodLister
+	TaskRunLister tektonlisters.TaskRunLister
+	stopCh        chan struct{}
+	hasSynced     []cache.InformerSynced
+}
+
+// Synced reports whether every informer backing this cache has completed its initial sync
+func (c *WorkerCache) Synced() bool {
+	for _, synced := range c.hasSynced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
 
-// WorkerConfigRegistry manages worker cluster configurations
+// WorkerConfigRegistry manages worker cluster configurations. It is agnostic to where those
+// configs come from: a CredentialSource supplies and refreshes them, and the registry's job is to
+// keep a per-cluster informer cache in sync with whatever the source currently reports.
 type WorkerConfigRegistry struct {
-	kubeClient  kubernetes.Interface
-	kueueClient kueueclient.Interface
-	config      *config.Config
-	configs     map[string]*rest.Config
-	mu          sync.RWMutex
+	source  CredentialSource
+	configs map[string]*rest.Config
+	caches  map[string]*WorkerCache
+	mu      sync.RWMutex
 }
 
-// NewWorkerConfigRegistry creates a new WorkerConfigRegistry
-func NewWorkerConfigRegistry(kubeClient kubernetes.Interface, kueueClient kueueclient.Interface, config *config.Config) *WorkerConfigRegistry {
+// NewWorkerConfigRegistry creates a new WorkerConfigRegistry backed by source and starts watching
+// it for changes.
+func NewWorkerConfigRegistry(source CredentialSource) *WorkerConfigRegistry {
 	registry := &WorkerConfigRegistry{
-		kubeClient:  kubeClient,
-		kueueClient: kueueClient,
-		config:      config,
-		configs:     make(map[string]*rest.Config),
+		source:  source,
+		configs: make(map[string]*rest.Config),
+		caches:  make(map[string]*WorkerCache),
 	}
 
-	// Start watching for MultiKueueCluster changes
-	go registry.watchMultiKueueClusters()
+	ctx := context.Background()
+	if err := registry.Reload(ctx); err != nil {
+		klog.Errorf("Failed to load initial worker configs: %v", err)
+	}
+	source.Watch(ctx, func() {
+		if err := registry.Reload(ctx); err != nil {
+			klog.Errorf("Failed to reload worker configs: %v", err)
+		}
+	})
 
 	return registry
 }
@@ -57,95 +84,113 @@ func (r *WorkerConfigRegistry) GetConfig(clusterName string) (*rest.Config, erro
 	return config, nil
 }
 
-// LoadConfigs loads all worker configurations from MultiKueueCluster resources
-func (r *WorkerConfigRegistry) LoadConfigs(ctx context.Context) error {
+// Reload asks the CredentialSource for its current configs, starts informer caches for any newly
+// seen cluster, and tears down caches for clusters the source no longer reports.
+func (r *WorkerConfigRegistry) Reload(ctx context.Context) error {
+	configs, err := r.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// List MultiKueueCluster resources
-	clusters, err := r.kueueClient.KueueV1beta1().MultiKueueClusters().List(ctx, v1.ListOptions{})
+	for clusterName, workerConfig := range configs {
+		r.configs[clusterName] = workerConfig
+
+		if _, exists := r.caches[clusterName]; !exists {
+			workerCache, err := r.startCache(clusterName, workerConfig)
+			if err != nil {
+				klog.Errorf("Failed to start informer cache for cluster %s: %v", clusterName, err)
+				continue
+			}
+			r.caches[clusterName] = workerCache
+		}
+	}
+
+	for clusterName, c := range r.caches {
+		if _, stillKnown := configs[clusterName]; !stillKnown {
+			close(c.stopCh)
+			delete(r.caches, clusterName)
+			delete(r.configs, clusterName)
+			klog.Infof("Removed worker config and cache for cluster: %s", clusterName)
+		}
+	}
+
+	metrics.KnownWorkerClusters.Set(float64(len(r.configs)))
+	return nil
+}
+
+// startCache builds and starts the shared informers backing a worker cluster's Pod and TaskRun caches
+func (r *WorkerConfigRegistry) startCache(clusterName string, workerConfig *rest.Config) (*WorkerCache, error) {
+	kubeClient, err := kubernetes.NewForConfig(workerConfig)
 	if err != nil {
-		return fmt.Errorf("failed to list MultiKueueClusters: %v", err)
+		return nil, fmt.Errorf("failed to create kube client for cluster %s: %v", clusterName, err)
 	}
 
-	// Load each MultiKueueCluster
-	for _, cluster := range clusters.Items {
-		clusterName := cluster.Name
+	tektonClient, err := tektonclient.NewForConfig(workerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tekton client for cluster %s: %v", clusterName, err)
+	}
 
-		// Get the secret name from the cluster spec
-		if cluster.Spec.KubeConfig.LocationType != "Secret" {
-			klog.Warningf("MultiKueueCluster %s has unsupported location type: %s", clusterName, cluster.Spec.KubeConfig.LocationType)
-			continue
-		}
+	stopCh := make(chan struct{})
 
-		secretName := cluster.Spec.KubeConfig.Location
-		if secretName == "" {
-			klog.Warningf("MultiKueueCluster %s has empty secret location", clusterName)
-			continue
-		}
+	kubeFactory := informers.NewSharedInformerFactory(kubeClient, workerResyncPeriod)
+	podInformer := kubeFactory.Core().V1().Pods()
 
-		// Get the secret
-		secret, err := r.kubeClient.CoreV1().Secrets(r.config.WorkersSecretNamespace).Get(ctx, secretName, v1.GetOptions{})
-		if err != nil {
-			klog.Errorf("Failed to get secret %s for cluster %s: %v", secretName, clusterName, err)
-			continue
-		}
+	tektonFactory := tektoninformers.NewSharedInformerFactory(tektonClient, workerResyncPeriod)
+	taskRunInformer := tektonFactory.Tekton().V1().TaskRuns()
 
-		// Check if secret contains kubeconfig data
-		kubeconfigData, exists := secret.Data["kubeconfig"]
-		if !exists {
-			klog.Warningf("Secret %s does not contain kubeconfig data", secretName)
-			continue
-		}
+	// Force the informers into existence before starting the factories
+	podInformer.Informer()
+	taskRunInformer.Informer()
 
-		// Parse kubeconfig using clientcmd
-		config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigData)
-		if err != nil {
-			klog.Errorf("Failed to parse kubeconfig for cluster %s: %v", clusterName, err)
-			continue
-		}
+	kubeFactory.Start(stopCh)
+	tektonFactory.Start(stopCh)
 
-		r.configs[clusterName] = config
-		klog.Infof("Loaded worker config for cluster: %s (secret: %s)", clusterName, secretName)
+	workerCache := &WorkerCache{
+		PodLister:     podInformer.Lister(),
+		TaskRunLister: taskRunInformer.Lister(),
+		stopCh:        stopCh,
+		hasSynced: []cache.InformerSynced{
+			podInformer.Informer().HasSynced,
+			taskRunInformer.Informer().HasSynced,
+		},
 	}
 
-	return nil
+	klog.Infof("Started informer cache for worker cluster: %s", clusterName)
+	return workerCache, nil
 }
 
-// watchMultiKueueClusters watches for changes to MultiKueueCluster resources
-func (r *WorkerConfigRegistry) watchMultiKueueClusters() {
-	ctx := context.Background()
+// GetCache returns the informer-backed cache for a worker cluster, if one has been started
+func (r *WorkerConfigRegistry) GetCache(clusterName string) (*WorkerCache, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	// Initial load
-	if err := r.LoadConfigs(ctx); err != nil {
-		klog.Errorf("Failed to load initial worker configs: %v", err)
-	}
+	c, exists := r.caches[clusterName]
+	return c, exists
+}
 
-	// Watch for changes to MultiKueueCluster resources
-	watcher, err := r.kueueClient.KueueV1beta1().MultiKueueClusters().Watch(ctx, v1.ListOptions{})
-	if err != nil {
-		klog.Errorf("Failed to watch MultiKueueClusters: %v", err)
-		return
-	}
-	defer watcher.Stop()
-
-	for event := range watcher.ResultChan() {
-		switch event.Type {
-		case "ADDED", "MODIFIED":
-			// Reload all configs when MultiKueueClusters change
-			if err := r.LoadConfigs(ctx); err != nil {
-				klog.Errorf("Failed to reload worker configs: %v", err)
-			}
-		case "DELETED":
-			// Remove config for deleted MultiKueueCluster
-			if cluster, ok := event.Object.(*kueuev1beta1.MultiKueueCluster); ok {
-				r.mu.Lock()
-				delete(r.configs, cluster.Name)
-				r.mu.Unlock()
-				klog.Infof("Removed worker config for cluster: %s", cluster.Name)
-			}
+// CachesSynced reports whether every known worker cluster's informer cache has completed its initial sync.
+// It returns false (not yet ready) if no worker clusters are known at all.
+func (r *WorkerConfigRegistry) CachesSynced() bool {
+	return len(r.UnsyncedClusters()) == 0 && len(r.ListClusters()) > 0
+}
+
+// UnsyncedClusters returns the names of every known worker cluster whose informer cache has not
+// yet completed its initial sync, so callers (the proxy's /ready probe) can report exactly which
+// clusters are holding readiness back instead of a single opaque bool.
+func (r *WorkerConfigRegistry) UnsyncedClusters() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var unsynced []string
+	for clusterName, c := range r.caches {
+		if !c.Synced() {
+			unsynced = append(unsynced, clusterName)
 		}
 	}
+	return unsynced
 }
 
 // ListClusters returns a list of available worker clusters
@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// fakeCredentialSource is a CredentialSource test double that returns a fixed, mutable set of
+// configs and never watches for changes (Watch is a no-op), letting tests drive reloads directly
+// via Reload instead of through a background watch loop.
+type fakeCredentialSource struct {
+	configs map[string]*rest.Config
+}
+
+func (s *fakeCredentialSource) Load(ctx context.Context) (map[string]*rest.Config, error) {
+	return s.configs, nil
+}
+
+func (s *fakeCredentialSource) Watch(ctx context.Context, onChange func()) {}
+
+func TestWorkerConfigRegistry_ReloadAddsAndRemovesClusters(t *testing.T) {
+	source := &fakeCredentialSource{
+		configs: map[string]*rest.Config{
+			"worker-1": {Host: "https://worker-1.example.com"},
+		},
+	}
+
+	registry := NewWorkerConfigRegistry(source)
+
+	if got := registry.ListClusters(); len(got) != 1 || got[0] != "worker-1" {
+		t.Fatalf("got clusters %v, want [worker-1]", got)
+	}
+
+	config, err := registry.GetConfig("worker-1")
+	if err != nil {
+		t.Fatalf("GetConfig returned an error: %v", err)
+	}
+	if config.Host != "https://worker-1.example.com" {
+		t.Errorf("got host %q, want %q", config.Host, "https://worker-1.example.com")
+	}
+
+	if _, ok := registry.GetCache("worker-1"); !ok {
+		t.Fatal("expected an informer cache to have been started for worker-1")
+	}
+
+	// Drop worker-1 and add worker-2.
+	source.configs = map[string]*rest.Config{
+		"worker-2": {Host: "https://worker-2.example.com"},
+	}
+	if err := registry.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload returned an error: %v", err)
+	}
+
+	if _, err := registry.GetConfig("worker-1"); err == nil {
+		t.Error("expected worker-1 to have been removed")
+	}
+	if _, ok := registry.GetCache("worker-1"); ok {
+		t.Error("expected worker-1's informer cache to have been stopped and removed")
+	}
+
+	if got := registry.ListClusters(); len(got) != 1 || got[0] != "worker-2" {
+		t.Fatalf("got clusters %v, want [worker-2]", got)
+	}
+}
+
+func TestWorkerConfigRegistry_GetConfig_UnknownCluster(t *testing.T) {
+	registry := NewWorkerConfigRegistry(&fakeCredentialSource{configs: map[string]*rest.Config{}})
+
+	if _, err := registry.GetConfig("does-not-exist"); err == nil {
+		t.Error("expected an error for an unknown cluster")
+	}
+}
+
+func TestWorkerConfigRegistry_CachesSynced_FalseWithNoClusters(t *testing.T) {
+	registry := NewWorkerConfigRegistry(&fakeCredentialSource{configs: map[string]*rest.Config{}})
+
+	if registry.CachesSynced() {
+		t.Error("expected CachesSynced to be false when there are no known clusters")
+	}
+}
@@ -0,0 +1,96 @@
+package resolver
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// negativeCacheEntry is the value stored behind a *list.Element in negativeResultCache.
+type negativeCacheEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// negativeResultCache remembers, for a bounded TTL, that a lookup key recently found no matching
+// Workload. Without it, a burst of status polls for a PipelineRun that Kueue hasn't yet created a
+// Workload for would each fall through the informer cache and hit the API server directly. It is a
+// bounded LRU, evicting the least recently used entry once full, so a steady stream of distinct
+// PipelineRun names under sustained dispatch load can't grow the cache without limit.
+type negativeResultCache struct {
+	mu     sync.Mutex
+	size   int
+	ttl    time.Duration
+	ll     *list.List
+	misses map[string]*list.Element
+}
+
+// newNegativeResultCache creates a negativeResultCache holding at most size entries, each expiring
+// after ttl. A non-positive size or ttl disables caching: isNegative always reports false and put
+// is a no-op.
+func newNegativeResultCache(size int, ttl time.Duration) *negativeResultCache {
+	if size <= 0 || ttl <= 0 {
+		return &negativeResultCache{}
+	}
+
+	return &negativeResultCache{
+		size:   size,
+		ttl:    ttl,
+		ll:     list.New(),
+		misses: make(map[string]*list.Element, size),
+	}
+}
+
+// isNegative reports whether key was recorded as a miss within the cache's TTL.
+func (c *negativeResultCache) isNegative(key string) bool {
+	if c.misses == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.misses[key]
+	if !ok {
+		return false
+	}
+
+	entry := elem.Value.(*negativeCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.ll.Remove(elem)
+		delete(c.misses, key)
+		return false
+	}
+
+	c.ll.MoveToFront(elem)
+	return true
+}
+
+// put records key as a miss for the cache's TTL, evicting the least recently used entry if the
+// cache is already at capacity.
+func (c *negativeResultCache) put(key string) {
+	if c.misses == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.misses[key]; ok {
+		elem.Value.(*negativeCacheEntry).expiresAt = time.Now().Add(c.ttl)
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	entry := &negativeCacheEntry{key: key, expiresAt: time.Now().Add(c.ttl)}
+	elem := c.ll.PushFront(entry)
+	c.misses[key] = elem
+
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.misses, oldest.Value.(*negativeCacheEntry).key)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNegativeResultCache_PutAndIsNegative(t *testing.T) {
+	c := newNegativeResultCache(10, time.Minute)
+
+	if c.isNegative("ns/pr") {
+		t.Fatal("expected no negative result before put")
+	}
+
+	c.put("ns/pr")
+
+	if !c.isNegative("ns/pr") {
+		t.Fatal("expected negative result after put")
+	}
+	if c.isNegative("ns/other") {
+		t.Fatal("expected unrelated key to remain a miss")
+	}
+}
+
+func TestNegativeResultCache_Expiry(t *testing.T) {
+	c := newNegativeResultCache(10, time.Millisecond)
+	c.put("ns/pr")
+
+	time.Sleep(5 * time.Millisecond)
+
+	if c.isNegative("ns/pr") {
+		t.Fatal("expected entry to have expired")
+	}
+}
+
+func TestNegativeResultCache_DisabledWhenTTLNonPositive(t *testing.T) {
+	c := newNegativeResultCache(10, 0)
+	c.put("ns/pr")
+
+	if c.isNegative("ns/pr") {
+		t.Fatal("expected caching to be disabled for a non-positive TTL")
+	}
+}
+
+func TestNegativeResultCache_EvictsLeastRecentlyUsedAtCapacity(t *testing.T) {
+	c := newNegativeResultCache(2, time.Minute)
+
+	c.put("ns/a")
+	c.put("ns/b")
+	c.isNegative("ns/a") // touch "a" so "b" becomes the least recently used
+	c.put("ns/c")        // evicts "b"
+
+	if !c.isNegative("ns/a") {
+		t.Fatal("expected recently touched key to survive eviction")
+	}
+	if c.isNegative("ns/b") {
+		t.Fatal("expected least recently used key to be evicted")
+	}
+	if !c.isNegative("ns/c") {
+		t.Fatal("expected newly inserted key to be present")
+	}
+}
@@ -0,0 +1,248 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/khrm/proxy-aae/internal/config"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	tektoninformers "github.com/tektoncd/pipeline/pkg/client/informers/externalversions"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+	kueueclient "sigs.k8s.io/kueue/client-go/clientset/versioned"
+	kueueinformers "sigs.k8s.io/kueue/client-go/informers/externalversions"
+)
+
+const (
+	// PipelineRunAnnotation is the annotation used to link Workloads to PipelineRuns
+	PipelineRunAnnotation = "proxy.tekton.dev/pipelineRun"
+
+	// resyncPeriod controls how often the shared informers backing the resolver resync.
+	resyncPeriod = 10 * time.Minute
+
+	// negativeCacheTTL bounds how long a "no Workload found" result is remembered, so a burst of
+	// status polls issued while Kueue is still dispatching a PipelineRun doesn't thunder the API
+	// server with a List per request.
+	negativeCacheTTL = 5 * time.Second
+
+	// negativeCacheMaxSize bounds how many distinct "no Workload found" keys are remembered at
+	// once, so a steady stream of distinct PipelineRun names under sustained dispatch load can't
+	// grow the cache without limit; entries beyond this are evicted least-recently-used.
+	negativeCacheMaxSize = 1000
+
+	// byPipelineRunAnnotationIndex indexes Workloads by the namespace/name of the PipelineRun
+	// named in their PipelineRunAnnotation.
+	byPipelineRunAnnotationIndex = "byPipelineRunAnnotation"
+
+	// byPipelineRunOwnerIndex indexes Workloads by the namespace/name of any PipelineRun listed
+	// in their owner references.
+	byPipelineRunOwnerIndex = "byPipelineRunOwner"
+)
+
+// WorkloadResolver resolves worker clusters from Kueue Workload status. It watches Workloads and
+// PipelineRuns via shared informers started at construction time and resolves a PipelineRun to
+// its Workload through an index lookup, falling back to the API server only on a cache miss.
+type WorkloadResolver struct {
+	kueueClient kueueclient.Interface
+	kubeClient  kubernetes.Interface
+	config      *config.Config
+
+	workloadInformer cache.SharedIndexInformer
+	workloadIndexer  cache.Indexer
+
+	pipelineRunSynced cache.InformerSynced
+	negativeCache     *negativeResultCache
+}
+
+// WorkerCluster represents a resolved worker cluster
+type WorkerCluster struct {
+	Name              string   `json:"name,omitempty"`
+	State             string   `json:"state"`
+	NominatedClusters []string `json:"nominatedClusters,omitempty"`
+	WorkloadName      string   `json:"workloadName,omitempty"`
+}
+
+// NewWorkloadResolver creates a WorkloadResolver and starts the shared informer factories backing
+// it. kubeClient is unused for resolution itself but kept so a future lookup strategy doesn't
+// change the constructor's call sites.
+func NewWorkloadResolver(kubeClient kubernetes.Interface, kueueClient kueueclient.Interface, tektonClient tektonclient.Interface, cfg *config.Config) *WorkloadResolver {
+	stopCh := make(chan struct{})
+
+	kueueFactory := kueueinformers.NewSharedInformerFactory(kueueClient, resyncPeriod)
+	workloadInformer := kueueFactory.Kueue().V1beta1().Workloads().Informer()
+	if err := workloadInformer.AddIndexers(cache.Indexers{
+		byPipelineRunAnnotationIndex: indexWorkloadByPipelineRunAnnotation,
+		byPipelineRunOwnerIndex:      indexWorkloadByPipelineRunOwner,
+	}); err != nil {
+		klog.Errorf("Failed to add Workload indexers: %v", err)
+	}
+
+	tektonFactory := tektoninformers.NewSharedInformerFactory(tektonClient, resyncPeriod)
+	pipelineRunInformer := tektonFactory.Tekton().V1().PipelineRuns().Informer()
+
+	kueueFactory.Start(stopCh)
+	tektonFactory.Start(stopCh)
+
+	klog.Infof("Started Workload/PipelineRun informers for workload resolution")
+
+	return &WorkloadResolver{
+		kueueClient:       kueueClient,
+		kubeClient:        kubeClient,
+		config:            cfg,
+		workloadInformer:  workloadInformer,
+		workloadIndexer:   workloadInformer.GetIndexer(),
+		pipelineRunSynced: pipelineRunInformer.HasSynced,
+		negativeCache:     newNegativeResultCache(negativeCacheMaxSize, negativeCacheTTL),
+	}
+}
+
+// CachesSynced reports whether the Workload and PipelineRun informers have both completed their
+// initial sync, so callers (the proxy's /ready probe) can stay NotReady until resolution won't
+// silently fall back to an unindexed, unbounded List on every request.
+func (r *WorkloadResolver) CachesSynced() bool {
+	return r.workloadInformer.HasSynced() && r.pipelineRunSynced()
+}
+
+// ResolveWorkerCluster resolves the worker cluster for a given PipelineRun
+func (r *WorkloadResolver) ResolveWorkerCluster(ctx context.Context, namespace, pipelineRunName string) (*WorkerCluster, error) {
+	// Find Workload by annotation
+	workload, err := r.findWorkloadByPipelineRun(ctx, namespace, pipelineRunName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find workload for PipelineRun %s/%s: %v", namespace, pipelineRunName, err)
+	}
+
+	if workload == nil {
+		return nil, fmt.Errorf("no workload found for PipelineRun %s/%s", namespace, pipelineRunName)
+	}
+
+	// Check if workload is admitted
+	if workload.Status.Admission != nil {
+		clusterName := ""
+		if workload.Status.ClusterName != nil {
+			clusterName = *workload.Status.ClusterName
+		}
+		return &WorkerCluster{
+			Name:         clusterName,
+			State:        "Admitted",
+			WorkloadName: workload.Name,
+		}, nil
+	}
+
+	// Workload is still pending
+	return &WorkerCluster{
+		State:        "Dispatching",
+		WorkloadName: workload.Name,
+	}, nil
+}
+
+// findWorkloadByPipelineRun resolves the Workload linked to a PipelineRun via a single O(1)
+// indexer lookup, falling back to an API server List only when the informer cache has not (yet)
+// observed a matching Workload - e.g. immediately after Kueue creates it, before the watch event
+// propagates.
+func (r *WorkloadResolver) findWorkloadByPipelineRun(ctx context.Context, namespace, pipelineRunName string) (*kueuev1beta1.Workload, error) {
+	key := pipelineRunIndexKey(namespace, pipelineRunName)
+
+	if workload := r.lookupIndexed(key); workload != nil {
+		return workload, nil
+	}
+
+	if r.negativeCache.isNegative(key) {
+		return nil, nil
+	}
+
+	workload, err := r.findWorkloadByPipelineRunAPIFallback(ctx, namespace, pipelineRunName)
+	if err != nil {
+		return nil, err
+	}
+	if workload == nil {
+		r.negativeCache.put(key)
+	}
+	return workload, nil
+}
+
+// lookupIndexed returns the Workload indexed under key by either the annotation or owner-ref
+// index, or nil if neither index has an entry for it.
+func (r *WorkloadResolver) lookupIndexed(key string) *kueuev1beta1.Workload {
+	for _, indexName := range []string{byPipelineRunAnnotationIndex, byPipelineRunOwnerIndex} {
+		items, err := r.workloadIndexer.ByIndex(indexName, key)
+		if err != nil {
+			klog.Errorf("Failed to query %s index for %s: %v", indexName, key, err)
+			continue
+		}
+		if len(items) > 0 {
+			if workload, ok := items[0].(*kueuev1beta1.Workload); ok {
+				return workload
+			}
+		}
+	}
+	return nil
+}
+
+// findWorkloadByPipelineRunAPIFallback lists Workloads directly from the API server.
+func (r *WorkloadResolver) findWorkloadByPipelineRunAPIFallback(ctx context.Context, namespace, pipelineRunName string) (*kueuev1beta1.Workload, error) {
+	workloads, err := r.kueueClient.KueueV1beta1().Workloads(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workloads: %v", err)
+	}
+
+	for i := range workloads.Items {
+		workload := &workloads.Items[i]
+		if workload.Annotations[PipelineRunAnnotation] == pipelineRunName {
+			return workload, nil
+		}
+		for _, ownerRef := range workload.OwnerReferences {
+			if ownerRef.Kind == "PipelineRun" && ownerRef.Name == pipelineRunName {
+				return workload, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// GetWorkloadStatus returns the current status of a workload
+func (r *WorkloadResolver) GetWorkloadStatus(ctx context.Context, namespace, workloadName string) (*kueuev1beta1.Workload, error) {
+	return r.kueueClient.KueueV1beta1().Workloads(namespace).Get(ctx, workloadName, v1.GetOptions{})
+}
+
+// pipelineRunIndexKey builds the index/cache key identifying a PipelineRun.
+func pipelineRunIndexKey(namespace, pipelineRunName string) string {
+	return namespace + "/" + pipelineRunName
+}
+
+// indexWorkloadByPipelineRunAnnotation indexes a Workload under the namespace/name of the
+// PipelineRun named in its PipelineRunAnnotation, if any.
+func indexWorkloadByPipelineRunAnnotation(obj interface{}) ([]string, error) {
+	workload, ok := obj.(*kueuev1beta1.Workload)
+	if !ok {
+		return nil, nil
+	}
+
+	pipelineRunName, ok := workload.Annotations[PipelineRunAnnotation]
+	if !ok || pipelineRunName == "" {
+		return nil, nil
+	}
+	return []string{pipelineRunIndexKey(workload.Namespace, pipelineRunName)}, nil
+}
+
+// indexWorkloadByPipelineRunOwner indexes a Workload under the namespace/name of every
+// PipelineRun listed in its owner references.
+func indexWorkloadByPipelineRunOwner(obj interface{}) ([]string, error) {
+	workload, ok := obj.(*kueuev1beta1.Workload)
+	if !ok {
+		return nil, nil
+	}
+
+	var keys []string
+	for _, ownerRef := range workload.OwnerReferences {
+		if ownerRef.Kind == "PipelineRun" {
+			keys = append(keys, pipelineRunIndexKey(workload.Namespace, ownerRef.Name))
+		}
+	}
+	return keys, nil
+}
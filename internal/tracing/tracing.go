@@ -0,0 +1,16 @@
+// Package tracing provides the single OpenTelemetry tracer used to trace a request's path from
+// worker cluster resolution through to the upstream client call that serves it.
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies this proxy's spans in whatever OpenTelemetry SDK the operator wires up.
+const tracerName = "github.com/khrm/proxy-aae"
+
+// Tracer returns the tracer used for every span the proxy emits.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}